@@ -0,0 +1,136 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package onit
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// artifactsPath is the local directory to which test artifacts are written
+	artifactsPath = "onit-artifacts"
+)
+
+// artifactSelectors are the label selectors for the pods from which artifacts are captured
+var artifactSelectors = []string{
+	"app=onos,type=config",
+	"app=onos,type=topo",
+	"type=simulator",
+	"group=raft",
+}
+
+// captureArtifacts downloads the logs of the cluster's pods for the time window of the given test. Log lines are
+// prefixed with their timestamps, and lines logged after the test completed are omitted.
+func (c *ClusterController) captureArtifacts(testID string) error {
+	job, err := c.kubeclient.BatchV1().Jobs(c.clusterID).Get(testID, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	record, err := c.getRecord(*job)
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(artifactsPath, testID)
+	if err := os.MkdirAll(path, 0755); err != nil {
+		return err
+	}
+
+	options := corev1.PodLogOptions{
+		Timestamps: true,
+	}
+	if !record.StartTime.IsZero() {
+		options.SinceTime = &metav1.Time{Time: record.StartTime}
+	}
+
+	for _, selector := range artifactSelectors {
+		pods, err := c.kubeclient.CoreV1().Pods(c.clusterID).List(metav1.ListOptions{
+			LabelSelector: selector,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, pod := range pods.Items {
+			if err := c.downloadLogWindow(pod, filepath.Join(path, fmt.Sprintf("%s.log", pod.Name)), options, record.EndTime); err != nil {
+				return err
+			}
+
+			// If the pod was restarted during the test, also capture the logs of the previous container
+			if len(pod.Status.ContainerStatuses) > 0 && pod.Status.ContainerStatuses[0].RestartCount > 0 {
+				previous := options
+				previous.Previous = true
+				if err := c.downloadLogWindow(pod, filepath.Join(path, fmt.Sprintf("%s.previous.log", pod.Name)), previous, record.EndTime); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// downloadLogWindow downloads the timestamped logs of the given pod to the given path, omitting the lines logged
+// after the given end time. If the end time is zero, all the logs are downloaded.
+func (c *ClusterController) downloadLogWindow(pod corev1.Pod, path string, options corev1.PodLogOptions, end time.Time) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	req := c.kubeclient.CoreV1().Pods(c.clusterID).GetLogs(pod.Name, &options)
+	readCloser, err := req.Stream()
+	if err != nil {
+		return err
+	}
+	defer readCloser.Close()
+
+	return copyLogWindow(file, readCloser, end)
+}
+
+// copyLogWindow copies timestamped log lines from the given reader to the given writer until a line logged after
+// the given end time is read. Lines without a timestamp are copied.
+func copyLogWindow(writer io.Writer, reader io.Reader, end time.Time) error {
+	buf := bufio.NewReader(reader)
+	for {
+		line, err := buf.ReadString('\n')
+		if line != "" {
+			if !end.IsZero() {
+				if i := strings.IndexByte(line, ' '); i > 0 {
+					if timestamp, err := time.Parse(time.RFC3339Nano, line[:i]); err == nil && timestamp.After(end) {
+						return nil
+					}
+				}
+			}
+			if _, err := io.WriteString(writer, line); err != nil {
+				return err
+			}
+		}
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package onit
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCopyLogWindow(t *testing.T) {
+	logs := "2019-08-01T10:00:00.000000001Z starting\n" +
+		"continued line\n" +
+		"2019-08-01T10:00:05Z running\n" +
+		"2019-08-01T10:00:10.5Z after the test\n" +
+		"2019-08-01T10:00:11Z stopping"
+
+	tests := []struct {
+		name     string
+		end      time.Time
+		expected string
+	}{
+		{
+			name:     "no end time",
+			expected: logs,
+		},
+		{
+			name: "bounded",
+			end:  time.Date(2019, 8, 1, 10, 0, 10, 0, time.UTC),
+			expected: "2019-08-01T10:00:00.000000001Z starting\n" +
+				"continued line\n" +
+				"2019-08-01T10:00:05Z running\n",
+		},
+		{
+			name:     "ends before the logs",
+			end:      time.Date(2019, 8, 1, 9, 0, 0, 0, time.UTC),
+			expected: "",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			assert.NoError(t, copyLogWindow(&buf, strings.NewReader(logs), test.end))
+			assert.Equal(t, test.expected, buf.String())
+		})
+	}
+}
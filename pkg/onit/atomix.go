@@ -24,6 +24,59 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// atomixComponent is the Atomix component, comprising the Atomix controller and the Raft partitions
+type atomixComponent struct{}
+
+// Name returns the name of the Atomix component
+func (*atomixComponent) Name() string {
+	return "atomix"
+}
+
+// Description returns the description of the Atomix component
+func (*atomixComponent) Description() string {
+	return "Atomix controller and Raft partitions"
+}
+
+// ImageTags returns the default image tags for the Atomix controller and Raft partitions
+func (*atomixComponent) ImageTags() map[string]string {
+	return map[string]string{
+		"atomix": string(Latest),
+		"raft":   string(Latest),
+	}
+}
+
+// Setup sets up the Atomix controller and the Raft partitions. The controller must be ready before
+// partitions can be created.
+func (*atomixComponent) Setup(c *ClusterController) error {
+	if err := c.setupAtomixController(); err != nil {
+		return err
+	}
+	return c.createPartitionSet()
+}
+
+// AwaitReady waits for the Raft partitions to become ready
+func (*atomixComponent) AwaitReady(c *ClusterController) error {
+	return c.awaitPartitionsReady()
+}
+
+// Teardown deletes the Raft partitions and the Atomix controller. The custom resource definitions are
+// shared by all clusters and are not deleted.
+func (*atomixComponent) Teardown(c *ClusterController) error {
+	err := c.atomixclient.K8sV1alpha1().PartitionSets(c.clusterID).Delete("raft", &metav1.DeleteOptions{})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return err
+	}
+	if err := c.deleteDeployments("atomix-controller"); err != nil {
+		return err
+	}
+	return c.deleteServices("atomix-controller")
+}
+
+// Nodes returns no nodes. The Raft nodes are listed by partition.
+func (*atomixComponent) Nodes(c *ClusterController) ([]NodeInfo, error) {
+	return []NodeInfo{}, nil
+}
+
 // setupAtomixController sets up the Atomix controller and associated resources
 func (c *ClusterController) setupAtomixController() error {
 	if err := c.createAtomixPartitionSetResource(); err != nil {
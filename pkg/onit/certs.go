@@ -0,0 +1,196 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package onit
+
+import (
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/onosproject/onos-test/pkg/onit/pki"
+	"github.com/onosproject/onos-test/test/env"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// caCertKey is the secret key for the cluster's CA certificate
+	caCertKey = "onf.cacrt"
+
+	// caKeyKey is the CA secret key for the cluster's CA private key
+	caKeyKey = "onf.cakey"
+
+	// serverCertKey is the secret key for the onos services' server certificate
+	serverCertKey = "onos-config.crt"
+
+	// serverKeyKey is the secret key for the onos services' server private key
+	serverKeyKey = "onos-config.key"
+
+	// clientCertKey is the secret key for the client certificate
+	clientCertKey = "client1.crt"
+
+	// clientKeyKey is the secret key for the client private key
+	clientKeyKey = "client1.key"
+
+	// clientName is the common name of the client certificate
+	clientName = "client1"
+)
+
+// onosServices is the list of onos services to which the server certificate applies
+var onosServices = []string{
+	"onos-config",
+	"onos-topo",
+	"onos-gui",
+}
+
+// createOnosSecret creates a secret containing a newly generated CA and the certificates for configuring TLS
+// in onos nodes, clients and the ingress. The CA's private key is stored in a separate secret that is never
+// mounted into pods, and the certificates used by tests are stored in a secret mounted only into test pods.
+func (c *ClusterController) createOnosSecret() error {
+	ca, err := pki.NewCA(fmt.Sprintf("onit-%s-ca", c.clusterID))
+	if err != nil {
+		return err
+	}
+
+	dnsNames := []string{"localhost"}
+	for _, service := range onosServices {
+		dnsNames = append(dnsNames, c.getServiceDNSNames(service)...)
+	}
+	server, err := ca.Issue(pki.Request{
+		CommonName:  "onos-config",
+		DNSNames:    dnsNames,
+		IPAddresses: []net.IP{net.ParseIP("127.0.0.1")},
+		Server:      true,
+		Client:      true,
+	})
+	if err != nil {
+		return err
+	}
+
+	client, err := ca.Issue(pki.Request{
+		CommonName: clientName,
+		Client:     true,
+	})
+	if err != nil {
+		return err
+	}
+
+	ingress, err := ca.Issue(pki.Request{
+		CommonName: guiIngressHost,
+		DNSNames:   append([]string{guiIngressHost}, dnsNames...),
+		Server:     true,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := c.createCASecret(ca); err != nil {
+		return err
+	}
+	if err := c.createTestSecret(ca); err != nil {
+		return err
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      c.clusterID,
+			Namespace: c.clusterID,
+		},
+		Data: map[string][]byte{
+			caCertKey:               ca.Cert,
+			serverCertKey:           server.Cert,
+			serverKeyKey:            server.Key,
+			clientCertKey:           client.Cert,
+			clientKeyKey:            client.Key,
+			corev1.TLSCertKey:       ingress.Cert,
+			corev1.TLSPrivateKeyKey: ingress.Key,
+		},
+	}
+	_, err = c.kubeclient.CoreV1().Secrets(c.clusterID).Create(secret)
+	return err
+}
+
+// createCASecret creates the secret containing the cluster's CA. The secret is never mounted into pods.
+func (c *ClusterController) createCASecret(ca *pki.Certificate) error {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      c.getCASecretName(),
+			Namespace: c.clusterID,
+		},
+		Data: map[string][]byte{
+			caCertKey: ca.Cert,
+			caKeyKey:  ca.Key,
+		},
+	}
+	_, err := c.kubeclient.CoreV1().Secrets(c.clusterID).Create(secret)
+	return err
+}
+
+// createTestSecret creates a secret containing the client certificates with which tests check authentication,
+// issued by the cluster's CA so that test pods never need the CA's private key
+func (c *ClusterController) createTestSecret(ca *pki.Certificate) error {
+	identity, err := ca.Issue(pki.Request{
+		CommonName: env.TestIdentity,
+		Client:     true,
+	})
+	if err != nil {
+		return err
+	}
+
+	expired, err := ca.Issue(pki.Request{
+		CommonName: clientName,
+		Client:     true,
+		NotBefore:  time.Now().Add(-48 * time.Hour),
+		NotAfter:   time.Now().Add(-24 * time.Hour),
+	})
+	if err != nil {
+		return err
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      c.getTestSecretName(),
+			Namespace: c.clusterID,
+		},
+		Data: map[string][]byte{
+			env.TestIdentityCertKey: identity.Cert,
+			env.TestIdentityKeyKey:  identity.Key,
+			env.TestExpiredCertKey:  expired.Cert,
+			env.TestExpiredKeyKey:   expired.Key,
+		},
+	}
+	_, err = c.kubeclient.CoreV1().Secrets(c.clusterID).Create(secret)
+	return err
+}
+
+// getCASecretName returns the name of the secret containing the cluster's CA
+func (c *ClusterController) getCASecretName() string {
+	return fmt.Sprintf("%s-ca", c.clusterID)
+}
+
+// getTestSecretName returns the name of the secret containing the certificates issued for tests
+func (c *ClusterController) getTestSecretName() string {
+	return fmt.Sprintf("%s-test", c.clusterID)
+}
+
+// getServiceDNSNames returns the DNS names by which the given service can be reached within the cluster
+func (c *ClusterController) getServiceDNSNames(service string) []string {
+	return []string{
+		service,
+		fmt.Sprintf("%s.%s", service, c.clusterID),
+		fmt.Sprintf("%s.%s.svc", service, c.clusterID),
+		fmt.Sprintf("%s.%s.svc.cluster.local", service, c.clusterID),
+	}
+}
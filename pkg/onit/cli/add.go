@@ -15,7 +15,11 @@
 package cli
 
 import (
+	"errors"
 	"fmt"
+	"strings"
+	"time"
+
 	"github.com/onosproject/onos-test/pkg/onit"
 	"github.com/spf13/cobra"
 )
@@ -25,18 +29,34 @@ var (
 		# Add a simulator with a given name
 		onit add simulator simulator-1
 
+		# Add a simulator registered as a testdevice 2.0.0 device
+		onit add simulator --type TestDevice --version 2.0.0
+
+		# Add a fleet of 100 simulators named sim-0 through sim-99
+		onit add simulators --count 100 --prefix sim
+
+		# Add a fleet of simulators configured from a template rendered for each simulator
+		onit add simulators --count 10 --config-file ./device.json.tmpl --set domain=example.org
+
 		# Add a network of stratum switches that emulates a linear network topology with two nodes
-		onit add network stratum-linear -- --topo linear,2`
+		onit add network stratum-linear -- --topo linear,2
+
+		# Add a network of stratum switches that emulates a tree of depth 2 with a fanout of 3
+		onit add network stratum-tree -- --topo tree,2,3
+
+		# Add a network of stratum switches with a custom topology described in a file
+		onit add network stratum-custom --topo-file ./topology.yaml`
 )
 
 // getAddCommand returns a cobra "add" command for adding resources to the cluster
 func getAddCommand() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:     "add {simulator,network} [args]",
+		Use:     "add {simulator,simulators,network} [args]",
 		Short:   "Add resources to the cluster",
 		Example: addExample,
 	}
 	cmd.AddCommand(getAddSimulatorCommand())
+	cmd.AddCommand(getAddSimulatorsCommand())
 	cmd.AddCommand(getAddNetworkCommand())
 	cmd.AddCommand(getAddAppCommand())
 	return cmd
@@ -79,7 +99,6 @@ func getAddNetworkCommand() *cobra.Command {
 			}
 
 			// Create the network configuration
-
 			config := &onit.NetworkConfig{
 				Config: configName,
 			}
@@ -87,10 +106,18 @@ func getAddNetworkCommand() *cobra.Command {
 				config.MininetOptions = args[1:]
 			}
 
-			// Update number of devices in the network configuration
-			onit.ParseMininetOptions(config)
+			// Load the custom topology if a topology file was provided
+			if topoFile, _ := cmd.Flags().GetString("topo-file"); topoFile != "" {
+				topology, err := onit.LoadTopology(topoFile)
+				if err != nil {
+					exitError(err)
+				}
+				config.Topology = *topology
+				config.TopoType = onit.Custom
+			}
 
-			if err != nil {
+			// Update the topology and number of devices in the network configuration
+			if err := onit.ParseMininetOptions(config); err != nil {
 				exitError(err)
 			}
 
@@ -108,6 +135,7 @@ func getAddNetworkCommand() *cobra.Command {
 		cobra.BashCompCustom: {"__onit_get_clusters"},
 	}
 	cmd.Flags().StringP("preset", "p", "default", "simulator preset to apply")
+	cmd.Flags().String("topo-file", "", "the path to a YAML or JSON file describing a custom topology of switches, hosts and links")
 	return cmd
 }
 
@@ -128,6 +156,16 @@ func getAddSimulatorCommand() *cobra.Command {
 
 			// Create the simulator configuration from the configured preset
 			configName, _ := cmd.Flags().GetString("preset")
+			deviceType, _ := cmd.Flags().GetString("type")
+			version, _ := cmd.Flags().GetString("version")
+			image, _ := cmd.Flags().GetString("image")
+			port, _ := cmd.Flags().GetInt("port")
+			configFile, _ := cmd.Flags().GetString("config-file")
+			setFlags, _ := cmd.Flags().GetStringArray("set")
+			values, err := parseSetValues(setFlags)
+			if err != nil {
+				exitError(err)
+			}
 
 			// Get the onit controller
 			controller, err := onit.NewController()
@@ -149,7 +187,13 @@ func getAddSimulatorCommand() *cobra.Command {
 
 			// Create the simulator configuration
 			config := &onit.SimulatorConfig{
-				Config: configName,
+				Config:     configName,
+				Type:       deviceType,
+				Version:    version,
+				Image:      image,
+				Port:       port,
+				ConfigFile: configFile,
+				Values:     values,
 			}
 
 			// Add the simulator to the cluster
@@ -166,6 +210,115 @@ func getAddSimulatorCommand() *cobra.Command {
 		cobra.BashCompCustom: {"__onit_get_clusters"},
 	}
 	cmd.Flags().StringP("preset", "p", "default", "simulator preset to apply")
+	cmd.Flags().String("type", "Devicesim", "the device type with which to register the simulator")
+	cmd.Flags().String("version", "1.0.0", "the device version with which to register the simulator")
+	cmd.Flags().String("image", "", "the simulator image, defaulting to onosproject/device-simulator with the simulator image tag")
+	cmd.Flags().Int("port", 11161, "the insecure gNMI port on which the simulator listens")
+	cmd.Flags().String("config-file", "", "the path to a simulator configuration template to use instead of the preset")
+	cmd.Flags().StringArray("set", []string{}, "a key=value to make available to the configuration template as .Values.<key>")
+	return cmd
+}
+
+// parseSetValues parses the given key=value pairs into a map of configuration template values
+func parseSetValues(values []string) (map[string]string, error) {
+	setValues := make(map[string]string)
+	for _, value := range values {
+		i := strings.Index(value, "=")
+		if i <= 0 {
+			return nil, fmt.Errorf("invalid value %s; must be of the form key=value", value)
+		}
+		setValues[value[:i]] = value[i+1:]
+	}
+	return setValues, nil
+}
+
+// getAddSimulatorsCommand returns a cobra command for deploying a fleet of device simulators
+func getAddSimulatorsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "simulators",
+		Short: "Add a fleet of device simulators to the test cluster",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			count, _ := cmd.Flags().GetInt("count")
+			prefix, _ := cmd.Flags().GetString("prefix")
+			configName, _ := cmd.Flags().GetString("preset")
+			deviceType, _ := cmd.Flags().GetString("type")
+			version, _ := cmd.Flags().GetString("version")
+			image, _ := cmd.Flags().GetString("image")
+			port, _ := cmd.Flags().GetInt("port")
+			configFile, _ := cmd.Flags().GetString("config-file")
+			setFlags, _ := cmd.Flags().GetStringArray("set")
+			timeout, _ := cmd.Flags().GetInt("timeout")
+			values, err := parseSetValues(setFlags)
+			if err != nil {
+				exitError(err)
+			}
+
+			if count < 1 {
+				exitError(errors.New("the number of simulators must be at least 1"))
+			}
+
+			// Get the onit controller
+			controller, err := onit.NewController()
+			if err != nil {
+				exitError(err)
+			}
+
+			// Get the cluster ID
+			clusterID, err := cmd.Flags().GetString("cluster")
+			if err != nil {
+				exitError(err)
+			}
+
+			// Get the cluster controller
+			cluster, err := controller.GetCluster(clusterID)
+			if err != nil {
+				exitError(err)
+			}
+
+			simulators, err := cluster.GetFleetSimulators(prefix)
+			if err != nil {
+				exitError(err)
+			}
+			if len(simulators) > 0 {
+				exitError(fmt.Errorf("a fleet of simulators with prefix %s already exists", prefix))
+			}
+
+			// Create the simulator configuration shared by the fleet
+			config := &onit.SimulatorConfig{
+				Config:     configName,
+				Type:       deviceType,
+				Version:    version,
+				Image:      image,
+				Port:       port,
+				ConfigFile: configFile,
+				Values:     values,
+			}
+
+			// Add the simulators to the cluster
+			if names, status := cluster.AddSimulators(prefix, count, config, time.Duration(timeout)*time.Second); status.Failed() {
+				exitStatus(status)
+			} else {
+				newPrinter(cmd).printNames(names)
+			}
+		},
+	}
+
+	cmd.Flags().StringP("cluster", "c", getDefaultCluster(), "the cluster to which to add the simulators")
+	cmd.Flags().Lookup("cluster").Annotations = map[string][]string{
+		cobra.BashCompCustom: {"__onit_get_clusters"},
+	}
+	cmd.Flags().IntP("count", "n", 1, "the number of simulators to add")
+	cmd.Flags().String("prefix", "sim", "the prefix of the simulator names, which are suffixed with the index of each simulator")
+	cmd.Flags().StringP("preset", "p", "default", "simulator preset to apply")
+	cmd.Flags().String("type", "Devicesim", "the device type with which to register the simulators")
+	cmd.Flags().String("version", "1.0.0", "the device version with which to register the simulators")
+	cmd.Flags().String("image", "", "the simulator image, defaulting to onosproject/device-simulator with the simulator image tag")
+	cmd.Flags().Int("port", 11161, "the insecure gNMI port on which the simulators listen")
+	cmd.Flags().String("config-file", "", "the path to a simulator configuration template to use instead of the preset")
+	cmd.Flags().StringArray("set", []string{}, "a key=value to make available to the configuration template as .Values.<key>")
+	cmd.Flags().IntP("timeout", "t", 60*5, "the number of seconds to wait for the simulators to become ready")
+	addOutputFlags(cmd)
 	return cmd
 }
 
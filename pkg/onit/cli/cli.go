@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/onosproject/onos-test/pkg/onit"
 	"github.com/onosproject/onos-test/pkg/runner"
 
 	"github.com/google/uuid"
@@ -56,25 +57,83 @@ func Subset(first, second []string) bool {
 	return true
 }
 
+const (
+	// clusterArgAnnotation marks commands that take the cluster they operate on as their first argument
+	clusterArgAnnotation = "onit-cluster-arg"
+
+	// newClusterAnnotation marks commands that create a cluster in the kubeconfig's current context
+	newClusterAnnotation = "onit-new-cluster"
+)
+
+// getTargetCluster returns the ID of the cluster on which the given command operates
+func getTargetCluster(cmd *cobra.Command, args []string) string {
+	if _, ok := cmd.Annotations[clusterArgAnnotation]; ok && len(args) > 0 {
+		return args[0]
+	}
+	if flag := cmd.Flags().Lookup("cluster"); flag != nil {
+		return flag.Value.String()
+	}
+	return getDefaultCluster()
+}
+
 // GetOnitCommand returns a Cobra command for tests in the given test registry
 func GetOnitCommand(registry *runner.TestRegistry) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:                    "onit",
 		Short:                  "Run onos integration tests on Kubernetes",
 		BashCompletionFunction: bashCompletion,
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+			progress, _ := cmd.Flags().GetString("progress")
+			if err := console.SetProgressMode(console.ProgressMode(progress)); err != nil {
+				exitError(err)
+			}
+
+			// Unless a context is specified, use the context in which the target cluster was deployed
+			kubeconfig, _ := cmd.Flags().GetString("kubeconfig")
+			context, _ := cmd.Flags().GetString("context")
+			if cmd.Flags().Changed("context") || cmd.Flags().Changed("kubeconfig") {
+				onit.SetKubeConfig(kubeconfig, context)
+				return
+			}
+			if _, ok := cmd.Annotations[newClusterAnnotation]; ok {
+				onit.SetKubeConfig(kubeconfig, "")
+				return
+			}
+
+			clusterID := getTargetCluster(cmd, args)
+			context = getClusterContext(clusterID)
+			onit.SetKubeConfig(kubeconfig, context)
+
+			// If the remembered context no longer exists, fail rather than target another Kubernetes cluster
+			if context != "" {
+				if ok, err := onit.HasKubeContext(context); err != nil {
+					exitError(err)
+				} else if !ok {
+					exitError(fmt.Errorf("context %s of cluster %s not found in kubeconfig; use --context to select a context", context, clusterID))
+				}
+			}
+		},
 	}
+	cmd.PersistentFlags().String("progress", string(console.ProgressAuto), "the format in which to output progress (auto, tty, plain, json)")
+	cmd.PersistentFlags().String("kubeconfig", "", "the path to the kubeconfig file to use (defaults to $KUBECONFIG or ~/.kube/config)")
+	cmd.PersistentFlags().String("context", "", "the kubeconfig context to use (defaults to the context of the default cluster)")
 	cmd.AddCommand(getCreateCommand())
 	cmd.AddCommand(getAddCommand())
 	cmd.AddCommand(getRemoveCommand())
+	cmd.AddCommand(getNetworkCommand())
 	cmd.AddCommand(getDeleteCommand())
 	cmd.AddCommand(getRunCommand(registry))
+	cmd.AddCommand(getRerunCommand())
+	cmd.AddCommand(getCancelCommand())
 	cmd.AddCommand(getGetCommand(registry))
 	cmd.AddCommand(getSetCommand())
 	cmd.AddCommand(getDebugCommand())
 	cmd.AddCommand(getFetchCommand())
+	cmd.AddCommand(getExportCommand())
 	cmd.AddCommand(getCompletionCommand())
 	cmd.AddCommand(getSSHCommand())
 	cmd.AddCommand(getOnosCliCommand())
+	cmd.AddCommand(getDashboardCommand())
 
 	return cmd
 }
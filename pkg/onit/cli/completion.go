@@ -103,7 +103,7 @@ __onit_custom_func() {
             return
 			;;
 		
-		onit_remove_network)
+		onit_remove_network | onit_get_network | onit_network_link | onit_network_switch)
             if [[ ${#nouns[@]} -eq 0 ]]; then
                 __onit_get_networks
             fi
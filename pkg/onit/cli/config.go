@@ -20,6 +20,7 @@ import (
 	"runtime"
 
 	"github.com/mitchellh/go-homedir"
+	"github.com/onosproject/onos-test/pkg/onit"
 	"github.com/spf13/viper"
 )
 
@@ -79,20 +80,45 @@ func getStorePresets() []string {
 	return configs
 }
 
-// setDefaultCluster sets the default cluster
+// setDefaultCluster sets the default cluster, remembering the kubeconfig context in which the cluster is deployed
 func setDefaultCluster(clusterID string) error {
 	if err := initConfig(); err != nil {
 		return err
 	}
+	if clusterID != "" {
+		kubeContext, err := onit.GetKubeContext()
+		if err != nil {
+			return err
+		}
+		contexts := viper.GetStringMapString("contexts")
+		contexts[clusterID] = kubeContext
+		viper.Set("contexts", contexts)
+	}
 	viper.Set("cluster", clusterID)
 	return viper.WriteConfig()
 }
 
+// forgetClusterContext forgets the kubeconfig context remembered for the given cluster
+func forgetClusterContext(clusterID string) error {
+	if err := initConfig(); err != nil {
+		return err
+	}
+	contexts := viper.GetStringMapString("contexts")
+	delete(contexts, clusterID)
+	viper.Set("contexts", contexts)
+	return viper.WriteConfig()
+}
+
 // getDefaultCluster returns the default cluster
 func getDefaultCluster() string {
 	return viper.GetString("cluster")
 }
 
+// getClusterContext returns the kubeconfig context remembered for the given cluster
+func getClusterContext(clusterID string) string {
+	return viper.GetStringMapString("contexts")[clusterID]
+}
+
 func initConfig() error {
 	// If the configuration file is not found, initialize a configuration in the home dir.
 	if err := viper.ReadInConfig(); err != nil {
@@ -16,6 +16,7 @@ package cli
 
 import (
 	"fmt"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 
@@ -34,6 +35,15 @@ var (
 		# Create a cluster that has two 3-node raft partitions
 		onit create cluster --partitions 2 --partition-size 3
 
+		# Create a cluster that exposes its services on the Kubernetes nodes' ports rather than through an ingress
+		onit create cluster --expose nodeport
+
+		# Create a cluster that loads an additional model plugin copied from a Docker image
+		onit create cluster --model-plugin mydevice:1.0.0=myorg/mydevice-plugin:latest
+
+		# Create a minimal cluster without the GUI, CLI, Envoy proxies or ingress
+		onit create cluster --without gui,cli,proxy,ingress
+
 		# Create a cluster that fetches docker images from a private docker registry
 		onit create cluster --docker-registry <host>:<port>
 	
@@ -41,6 +51,9 @@ var (
         onit create cluster --image-tags topo=test-topo-tag,config=test-config-tag`
 )
 
+// withoutIngress is the --without value that disables the ingress
+const withoutIngress = "ingress"
+
 // getCreateCommand returns a cobra "setup" command for setting up resources
 func getCreateCommand() *cobra.Command {
 	cmd := &cobra.Command{
@@ -52,43 +65,46 @@ func getCreateCommand() *cobra.Command {
 	return cmd
 }
 
+// initImageTags sets the default tag for each image whose tag is not specified
 func initImageTags(imageTags map[string]string) {
-	if imageTags["config"] == "" {
-		imageTags["config"] = string(onit.Debug)
-	}
-	if imageTags["topo"] == "" {
-		imageTags["topo"] = string(onit.Debug)
-	}
-	if imageTags["gui"] == "" {
-		imageTags["gui"] = string(onit.Latest)
-	}
-	if imageTags["cli"] == "" {
-		imageTags["cli"] = string(onit.Latest)
-	}
-	if imageTags["atomix"] == "" {
-		imageTags["atomix"] = string(onit.Latest)
-	}
-	if imageTags["raft"] == "" {
-		imageTags["raft"] = string(onit.Latest)
-	}
-	if imageTags["simulator"] == "" {
-		imageTags["simulator"] = string(onit.Latest)
-	}
-	if imageTags["stratum"] == "" {
-		imageTags["stratum"] = string(onit.Latest)
-	}
-	if imageTags["test"] == "" {
-		imageTags["test"] = string(onit.Latest)
+	for name, tag := range onit.GetDefaultImageTags() {
+		if imageTags[name] == "" {
+			imageTags[name] = tag
+		}
 	}
+}
 
+// parseModelPlugins returns the default model plugins with the given type:version[=path|image:path] plugins added,
+// overriding any default plugin of the same type and version
+func parseModelPlugins(values []string) ([]onit.ModelPlugin, error) {
+	plugins := onit.GetDefaultModelPlugins()
+	for _, value := range values {
+		plugin, err := onit.ParseModelPlugin(value)
+		if err != nil {
+			return nil, err
+		}
+
+		replaced := false
+		for i, existing := range plugins {
+			if existing.Type == plugin.Type && existing.Version == plugin.Version {
+				plugins[i] = plugin
+				replaced = true
+			}
+		}
+		if !replaced {
+			plugins = append(plugins, plugin)
+		}
+	}
+	return plugins, nil
 }
 
 // getCreateClusterCommand returns a cobra command for deploying a test cluster
 func getCreateClusterCommand() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "cluster [id]",
-		Short: "Setup a test cluster on Kubernetes",
-		Args:  cobra.MaximumNArgs(1),
+		Use:         "cluster [id]",
+		Short:       "Setup a test cluster on Kubernetes",
+		Args:        cobra.MaximumNArgs(1),
+		Annotations: map[string]string{newClusterAnnotation: "true"},
 		Run: func(cmd *cobra.Command, args []string) {
 			dockerRegistry, _ := cmd.Flags().GetString("docker-registry")
 			configNodes, _ := cmd.Flags().GetInt("config-nodes")
@@ -99,11 +115,39 @@ func getCreateClusterCommand() *cobra.Command {
 			imageTags, _ := cmd.Flags().GetStringToString("image-tags")
 			imagePullPolicy, _ := cmd.Flags().GetString("image-pull-policy")
 			pullPolicy := corev1.PullPolicy(imagePullPolicy)
+			expose, _ := cmd.Flags().GetString("expose")
+			exposure := onit.ExposureType(strings.ToLower(expose))
+			without, _ := cmd.Flags().GetStringSlice("without")
+			pluginFlags, _ := cmd.Flags().GetStringArray("model-plugin")
 
 			if pullPolicy != corev1.PullAlways && pullPolicy != corev1.PullIfNotPresent && pullPolicy != corev1.PullNever {
 				exitError(fmt.Errorf("invalid pull policy; must of one of %s, %s or %s", corev1.PullAlways, corev1.PullIfNotPresent, corev1.PullNever))
 			}
 
+			if exposure != onit.ExposeNone && exposure != onit.ExposeIngress && exposure != onit.ExposeNodePort && exposure != onit.ExposeLoadBalancer {
+				exitError(fmt.Errorf("invalid exposure; must be one of %s, %s, %s or %s", onit.ExposeNone, onit.ExposeIngress, onit.ExposeNodePort, onit.ExposeLoadBalancer))
+			}
+
+			// Disable the components and ingress listed by the --without flag
+			components := make(map[string]bool)
+			for _, name := range without {
+				if name == withoutIngress {
+					if exposure == onit.ExposeIngress {
+						exposure = onit.ExposeNone
+					}
+					continue
+				}
+				if _, err := onit.GetComponent(name); err != nil {
+					exitError(err)
+				}
+				components[name] = false
+			}
+
+			modelPlugins, err := parseModelPlugins(pluginFlags)
+			if err != nil {
+				exitError(err)
+			}
+
 			initImageTags(imageTags)
 
 			// Get the onit controller
@@ -130,6 +174,9 @@ func getCreateClusterCommand() *cobra.Command {
 				TopoNodes:     topoNodes,
 				Partitions:    partitions,
 				PartitionSize: partitionSize,
+				Exposure:      exposure,
+				Components:    components,
+				ModelPlugins:  modelPlugins,
 			}
 
 			// Create the cluster controller
@@ -153,16 +200,7 @@ func getCreateClusterCommand() *cobra.Command {
 		},
 	}
 
-	imageTags := make(map[string]string)
-	imageTags["config"] = string(onit.Debug)
-	imageTags["topo"] = string(onit.Debug)
-	imageTags["simulator"] = string(onit.Latest)
-	imageTags["stratum"] = string(onit.Latest)
-	imageTags["test"] = string(onit.Latest)
-	imageTags["atomix"] = string(onit.Latest)
-	imageTags["raft"] = string(onit.Latest)
-	imageTags["gui"] = string(onit.Latest)
-	imageTags["cli"] = string(onit.Latest)
+	imageTags := onit.GetDefaultImageTags()
 
 	cmd.Flags().StringP("config", "c", "default", "test cluster configuration")
 	cmd.Flags().String("docker-registry", "", "an optional host:port for a private Docker registry")
@@ -172,6 +210,9 @@ func getCreateClusterCommand() *cobra.Command {
 	cmd.Flags().IntP("partition-size", "s", 1, "the size of each Raft partition")
 	cmd.Flags().StringToString("image-tags", imageTags, "the image docker container tag for each node in the cluster")
 	cmd.Flags().String("image-pull-policy", string(corev1.PullIfNotPresent), "the Docker image pull policy")
+	cmd.Flags().String("expose", string(onit.ExposeIngress), "how to expose services outside Kubernetes (none, ingress, nodeport, loadbalancer)")
+	cmd.Flags().StringSlice("without", []string{}, "components to omit from the cluster (e.g. gui, cli, proxy, ingress)")
+	cmd.Flags().StringArray("model-plugin", []string{}, "a type:version[=path|image:path] model plugin to load in addition to the default plugins")
 
 	return cmd
 }
@@ -0,0 +1,491 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/onosproject/onos-test/pkg/onit"
+	"github.com/spf13/cobra"
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+var (
+	dashboardExample = `
+		# Open a dashboard for the current cluster
+		onit dashboard
+
+		# Open a dashboard refreshing every 5 seconds
+		onit dashboard --interval 5s`
+)
+
+const (
+	// dashboardRecentTests is the number of completed test runs shown in the dashboard
+	dashboardRecentTests = 10
+
+	dashboardHelp = "↑/↓ select   l logs   s shell   k kill node   r refresh   q quit"
+)
+
+// dashboardItemKind is the kind of a resource that can be selected in the dashboard
+type dashboardItemKind string
+
+const (
+	dashboardNode      dashboardItemKind = "node"
+	dashboardSimulator dashboardItemKind = "simulator"
+	dashboardNetwork   dashboardItemKind = "network"
+	dashboardTest      dashboardItemKind = "test"
+)
+
+// dashboardItem is a resource that can be selected in the dashboard
+type dashboardItem struct {
+	id   string
+	kind dashboardItemKind
+}
+
+// dashboardRow is a row in a section of the dashboard
+type dashboardRow struct {
+	item   *dashboardItem
+	values []interface{}
+}
+
+// dashboard is a terminal UI showing the state of a cluster
+type dashboard struct {
+	clusterID string
+	cluster   *onit.ClusterController
+	state     *terminal.State
+	keys      chan string
+	resume    chan struct{}
+	lines     []string
+	items     []dashboardItem
+	itemLines []int
+	selected  int
+	message   string
+	confirm   func()
+}
+
+// getDashboardCommand returns a cobra "dashboard" command to open a terminal UI for a cluster
+func getDashboardCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "dashboard",
+		Short:   "Open a dashboard showing the state of a cluster",
+		Example: dashboardExample,
+		Args:    cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			if !terminal.IsTerminal(int(os.Stdin.Fd())) || !terminal.IsTerminal(int(os.Stdout.Fd())) {
+				exitError(errors.New("the dashboard must be run in a terminal"))
+			}
+
+			// Get the onit controller
+			controller, err := onit.NewController()
+			if err != nil {
+				exitError(err)
+			}
+
+			// Get the cluster ID
+			clusterID, err := cmd.Flags().GetString("cluster")
+			if err != nil {
+				exitError(err)
+			}
+
+			// Get the cluster controller
+			cluster, err := controller.GetCluster(clusterID)
+			if err != nil {
+				exitError(err)
+			}
+
+			interval, _ := cmd.Flags().GetDuration("interval")
+			d := &dashboard{
+				clusterID: clusterID,
+				cluster:   cluster,
+				keys:      make(chan string),
+				resume:    make(chan struct{}, 1),
+			}
+			if err := d.run(interval); err != nil {
+				exitError(err)
+			}
+		},
+	}
+	cmd.Flags().StringP("cluster", "c", getDefaultCluster(), "the cluster to show")
+	cmd.Flags().Lookup("cluster").Annotations = map[string][]string{
+		cobra.BashCompCustom: {"__onit_get_clusters"},
+	}
+	cmd.Flags().Duration("interval", 2*time.Second, "the interval at which to refresh the dashboard")
+	return cmd
+}
+
+// run runs the dashboard until the user quits
+func (d *dashboard) run(interval time.Duration) error {
+	state, err := terminal.MakeRaw(int(os.Stdin.Fd()))
+	if err != nil {
+		return err
+	}
+	d.state = state
+	defer func() {
+		terminal.Restore(int(os.Stdin.Fd()), d.state)
+		fmt.Print("\x1b[2J\x1b[H")
+	}()
+
+	// Read keys from stdin. The reader waits for each key to be handled before reading the next key to allow
+	// handlers to hand stdin over to other readers.
+	go func() {
+		buf := make([]byte, 8)
+		for {
+			n, err := os.Stdin.Read(buf)
+			if err != nil {
+				close(d.keys)
+				return
+			}
+			d.keys <- string(buf[:n])
+			<-d.resume
+		}
+	}()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	d.refresh()
+	for {
+		d.draw()
+		select {
+		case <-ticker.C:
+			d.refresh()
+		case key, ok := <-d.keys:
+			if !ok {
+				return nil
+			}
+			quit := d.handleKey(key)
+			d.resume <- struct{}{}
+			if quit {
+				return nil
+			}
+		}
+	}
+}
+
+// handleKey handles a key pressed by the user, returning a boolean indicating whether to quit the dashboard
+func (d *dashboard) handleKey(key string) bool {
+	// If an action is awaiting confirmation, run it only if the user confirmed
+	if d.confirm != nil {
+		confirm := d.confirm
+		d.confirm = nil
+		d.message = ""
+		if key == "y" || key == "Y" {
+			confirm()
+		}
+		return false
+	}
+
+	d.message = ""
+	switch key {
+	case "q", "\x03":
+		return true
+	case "\x1b[A":
+		if d.selected > 0 {
+			d.selected--
+		}
+	case "\x1b[B":
+		if d.selected < len(d.items)-1 {
+			d.selected++
+		}
+	case "r":
+		d.refresh()
+	case "l":
+		if item := d.selectedItem(); item != nil {
+			d.tailLogs(item)
+		}
+	case "s":
+		if item := d.selectedItem(); item != nil {
+			d.openShell(item)
+		}
+	case "k":
+		if item := d.selectedItem(); item != nil {
+			if item.kind != dashboardNode {
+				d.message = fmt.Sprintf("%s is not a node", item.id)
+			} else {
+				d.message = fmt.Sprintf("Kill node %s? (y/n)", item.id)
+				d.confirm = func() {
+					if err := d.cluster.KillNode(item.id); err != nil {
+						d.message = err.Error()
+					} else {
+						d.message = fmt.Sprintf("Killed node %s", item.id)
+					}
+					d.refresh()
+				}
+			}
+		}
+	}
+	return false
+}
+
+// selectedItem returns the currently selected item
+func (d *dashboard) selectedItem() *dashboardItem {
+	if d.selected < 0 || d.selected >= len(d.items) {
+		return nil
+	}
+	return &d.items[d.selected]
+}
+
+// tailLogs streams the logs of the given item until the user presses a key
+func (d *dashboard) tailLogs(item *dashboardItem) {
+	resourceID := item.id
+	if item.kind == dashboardTest {
+		resources, err := d.cluster.GetResources(item.id)
+		if err != nil {
+			d.message = err.Error()
+			return
+		}
+		resourceID = resources[0]
+	}
+
+	reader, err := d.cluster.StreamLogs(resourceID)
+	if err != nil {
+		d.message = err.Error()
+		return
+	}
+
+	fmt.Print("\x1b[2J\x1b[H")
+	fmt.Printf("\x1b[1mLogs for %s (press any key to return)\x1b[0m\r\n", resourceID)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		scanner := bufio.NewScanner(reader)
+		for scanner.Scan() {
+			fmt.Printf("%s\r\n", scanner.Text())
+		}
+	}()
+
+	// Hand the key reader back to wait for the key ending the logs
+	d.resume <- struct{}{}
+	<-d.keys
+
+	// Close the stream and wait for the logs to stop before the dashboard is redrawn
+	reader.Close()
+	<-done
+}
+
+// openShell opens a shell to the given item, restoring the dashboard when the shell exits
+func (d *dashboard) openShell(item *dashboardItem) {
+	if item.kind == dashboardTest {
+		d.message = "cannot open a shell to a test run"
+		return
+	}
+
+	terminal.Restore(int(os.Stdin.Fd()), d.state)
+	fmt.Print("\x1b[2J\x1b[H")
+	err := d.cluster.OpenShell(item.id)
+	if state, rawErr := terminal.MakeRaw(int(os.Stdin.Fd())); rawErr == nil {
+		d.state = state
+	}
+	if err != nil {
+		d.message = err.Error()
+	}
+	d.refresh()
+}
+
+// refresh reloads the state of the cluster
+func (d *dashboard) refresh() {
+	var selectedID string
+	if item := d.selectedItem(); item != nil {
+		selectedID = item.id
+	}
+
+	d.lines = make([]string, 0)
+	d.items = make([]dashboardItem, 0)
+	d.itemLines = make([]int, 0)
+
+	if nodes, err := d.cluster.GetNodes(); err != nil {
+		d.addError("NODES", err)
+	} else {
+		rows := make([]dashboardRow, len(nodes))
+		for i, node := range nodes {
+			rows[i] = dashboardRow{
+				item:   &dashboardItem{id: node.ID, kind: dashboardNode},
+				values: []interface{}{node.ID, node.Type, node.Status, node.Ready, node.Restarts},
+			}
+		}
+		d.addSection("NODES", []string{"ID", "TYPE", "STATUS", "READY", "RESTARTS"}, rows)
+	}
+
+	if partitions, err := d.cluster.GetPartitions(); err != nil {
+		d.addError("PARTITIONS", err)
+	} else {
+		sort.Slice(partitions, func(i, j int) bool {
+			return partitions[i].Partition < partitions[j].Partition
+		})
+		rows := make([]dashboardRow, 0)
+		for _, partition := range partitions {
+			nodes, err := d.cluster.GetPartitionNodes(partition.Partition)
+			if err != nil {
+				d.addError("PARTITIONS", err)
+				continue
+			}
+			for _, node := range nodes {
+				rows = append(rows, dashboardRow{
+					item:   &dashboardItem{id: node.ID, kind: dashboardNode},
+					values: []interface{}{partition.Partition, partition.Group, node.ID, node.Status, node.Ready, node.Restarts},
+				})
+			}
+		}
+		d.addSection("PARTITIONS", []string{"PARTITION", "GROUP", "NODE", "STATUS", "READY", "RESTARTS"}, rows)
+	}
+
+	if simulators, err := d.cluster.GetSimulatorNodes(); err != nil {
+		d.addError("SIMULATORS", err)
+	} else {
+		rows := make([]dashboardRow, len(simulators))
+		for i, simulator := range simulators {
+			rows[i] = dashboardRow{
+				item:   &dashboardItem{id: simulator.ID, kind: dashboardSimulator},
+				values: []interface{}{simulator.ID, simulator.Status, simulator.Ready, simulator.Restarts},
+			}
+		}
+		d.addSection("SIMULATORS", []string{"ID", "STATUS", "READY", "RESTARTS"}, rows)
+	}
+
+	if networks, err := d.cluster.GetNetworks(); err != nil {
+		d.addError("NETWORKS", err)
+	} else {
+		rows := make([]dashboardRow, len(networks))
+		for i, network := range networks {
+			rows[i] = dashboardRow{
+				item:   &dashboardItem{id: network, kind: dashboardNetwork},
+				values: []interface{}{network},
+			}
+		}
+		d.addSection("NETWORKS", []string{"ID"}, rows)
+	}
+
+	if records, err := d.cluster.GetHistory(); err != nil {
+		d.addError("TESTS", err)
+	} else {
+		sort.Slice(records, func(i, j int) bool {
+			return records[i].StartTime.After(records[j].StartTime)
+		})
+		rows := make([]dashboardRow, 0)
+		completed := 0
+		for _, record := range records {
+			if record.Status != onit.TestRunning {
+				if completed == dashboardRecentTests {
+					continue
+				}
+				completed++
+			}
+			rows = append(rows, dashboardRow{
+				item:   &dashboardItem{id: record.TestID, kind: dashboardTest},
+				values: []interface{}{record.TestID, strings.Join(record.Args, ","), record.Status, formatTime(record.StartTime), formatDuration(record.Duration)},
+			})
+		}
+		d.addSection("TESTS", []string{"ID", "TESTS", "STATUS", "STARTED", "DURATION"}, rows)
+	}
+
+	// Keep the previously selected item selected if it still exists
+	d.selected = 0
+	for i, item := range d.items {
+		if item.id == selectedID {
+			d.selected = i
+			break
+		}
+	}
+}
+
+// addSection adds a table of rows to the dashboard
+func (d *dashboard) addSection(title string, headers []string, rows []dashboardRow) {
+	var buf bytes.Buffer
+	writer := new(tabwriter.Writer)
+	writer.Init(&buf, 0, 0, 3, ' ', tabwriter.FilterHTML)
+	fmt.Fprintln(writer, strings.Join(headers, "\t"))
+	for _, row := range rows {
+		values := make([]string, len(row.values))
+		for i, value := range row.values {
+			values[i] = fmt.Sprint(value)
+		}
+		fmt.Fprintln(writer, strings.Join(values, "\t"))
+	}
+	writer.Flush()
+
+	d.lines = append(d.lines, fmt.Sprintf("\x1b[1m%s\x1b[0m", title))
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	d.lines = append(d.lines, lines[0])
+	for i, row := range rows {
+		d.items = append(d.items, *row.item)
+		d.itemLines = append(d.itemLines, len(d.lines))
+		d.lines = append(d.lines, lines[i+1])
+	}
+	d.lines = append(d.lines, "")
+}
+
+// addError adds an error loading a section to the dashboard
+func (d *dashboard) addError(title string, err error) {
+	d.lines = append(d.lines, fmt.Sprintf("\x1b[1m%s\x1b[0m", title), err.Error(), "")
+}
+
+// draw draws the dashboard to the terminal
+func (d *dashboard) draw() {
+	width, height, err := terminal.GetSize(int(os.Stdout.Fd()))
+	if err != nil {
+		width, height = 80, 24
+	}
+
+	selectedLine := -1
+	if d.selected < len(d.itemLines) {
+		selectedLine = d.itemLines[d.selected]
+	}
+
+	// Scroll the content to keep the selected item visible between the header and footer
+	visible := height - 3
+	if visible < 1 {
+		visible = 1
+	}
+	offset := 0
+	if selectedLine >= visible {
+		offset = selectedLine - visible + 1
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("\x1b[H\x1b[2J")
+	fmt.Fprintf(&buf, "\x1b[7m%s\x1b[0m\r\n\r\n", truncate(fmt.Sprintf(" onit dashboard: %s  %s", d.clusterID, time.Now().Format(time.RFC3339)), width))
+	for i := offset; i < len(d.lines) && i < offset+visible; i++ {
+		line := truncate(d.lines[i], width)
+		if i == selectedLine {
+			fmt.Fprintf(&buf, "\x1b[7m%s\x1b[0m\r\n", line)
+		} else {
+			fmt.Fprintf(&buf, "%s\r\n", line)
+		}
+	}
+
+	footer := dashboardHelp
+	if d.message != "" {
+		footer = d.message
+	}
+	fmt.Fprintf(&buf, "\x1b[%d;1H%s", height, truncate(footer, width))
+	os.Stdout.Write(buf.Bytes())
+}
+
+// truncate truncates the given line to the given width
+func truncate(line string, width int) string {
+	runes := []rune(line)
+	if len(runes) > width && !strings.Contains(line, "\x1b") {
+		return string(runes[:width])
+	}
+	return line
+}
@@ -15,6 +15,8 @@
 package cli
 
 import (
+	"fmt"
+
 	"github.com/onosproject/onos-test/pkg/onit"
 	"github.com/spf13/cobra"
 )
@@ -25,7 +27,13 @@ var (
 		onit delete cluster <name of cluster>
 
 		# Delete the currently configured cluster
-		onit delete cluster`
+		onit delete cluster
+
+		# Delete all completed test runs from the cluster's history
+		onit delete history
+
+		# Delete the test runs that were started more than a day ago
+		onit delete history --older-than 24h`
 )
 
 // getDeleteCommand returns a cobra "teardown" command for tearing down Kubernetes test resources
@@ -36,15 +44,17 @@ func getDeleteCommand() *cobra.Command {
 		Example: deleteExample,
 	}
 	cmd.AddCommand(getDeleteClusterCommand())
+	cmd.AddCommand(getDeleteHistoryCommand())
 	return cmd
 }
 
 // getDeleteClusterCommand returns a cobra "teardown" command for tearing down a test cluster
 func getDeleteClusterCommand() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "cluster [id]",
-		Short: "Delete a test cluster on Kubernetes",
-		Args:  cobra.MaximumNArgs(1),
+		Use:         "cluster [id]",
+		Short:       "Delete a test cluster on Kubernetes",
+		Args:        cobra.MaximumNArgs(1),
+		Annotations: map[string]string{clusterArgAnnotation: "true"},
 		Run: func(cmd *cobra.Command, args []string) {
 			// Create the onit controller
 			controller, err := onit.NewController()
@@ -62,6 +72,9 @@ func getDeleteClusterCommand() *cobra.Command {
 
 			// Delete the cluster
 			status := controller.DeleteCluster(clusterID)
+			if err := forgetClusterContext(clusterID); err != nil {
+				exitError(err)
+			}
 			err = setDefaultCluster("")
 			if err != nil {
 				exitError(err)
@@ -73,3 +86,48 @@ func getDeleteClusterCommand() *cobra.Command {
 	}
 	return cmd
 }
+
+// getDeleteHistoryCommand returns a cobra command for pruning the test history of a cluster
+func getDeleteHistoryCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: "Delete completed test runs from the cluster",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			// Get the onit controller
+			controller, err := onit.NewController()
+			if err != nil {
+				exitError(err)
+			}
+
+			// Get the cluster ID
+			clusterID, err := cmd.Flags().GetString("cluster")
+			if err != nil {
+				exitError(err)
+			}
+
+			// Get the cluster controller
+			cluster, err := controller.GetCluster(clusterID)
+			if err != nil {
+				exitError(err)
+			}
+
+			// Delete the test runs and output the IDs of the deleted runs
+			olderThan, _ := cmd.Flags().GetDuration("older-than")
+			deleted, status := cluster.DeleteHistory(olderThan)
+			for _, testID := range deleted {
+				fmt.Println(testID)
+			}
+			if status.Failed() {
+				exitStatus(status)
+			}
+		},
+	}
+
+	cmd.Flags().StringP("cluster", "c", getDefaultCluster(), "the cluster from which to delete the history")
+	cmd.Flags().Lookup("cluster").Annotations = map[string][]string{
+		cobra.BashCompCustom: {"__onit_get_clusters"},
+	}
+	cmd.Flags().Duration("older-than", 0, "only delete test runs started longer ago than a relative duration like 5s, 2m, or 3h")
+	return cmd
+}
@@ -0,0 +1,89 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"github.com/onosproject/onos-test/pkg/onit"
+	"github.com/spf13/cobra"
+)
+
+var (
+	exportExample = `
+		# Export the records and logs of all test runs to the current directory
+		onit export history
+
+		# Export the failed test runs to a directory
+		onit export history --status failed -d ./results`
+)
+
+// getExportCommand returns a cobra "export" command for exporting resources from a test cluster
+func getExportCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "export {history}",
+		Short:   "Export resources from the cluster",
+		Example: exportExample,
+	}
+	cmd.AddCommand(getExportHistoryCommand())
+	return cmd
+}
+
+// getExportHistoryCommand returns a cobra command for exporting the test history to disk
+func getExportHistoryCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: "Export the records and logs of test runs",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			// Get the onit controller
+			controller, err := onit.NewController()
+			if err != nil {
+				exitError(err)
+			}
+
+			// Get the cluster ID
+			clusterID, err := cmd.Flags().GetString("cluster")
+			if err != nil {
+				exitError(err)
+			}
+
+			// Get the cluster controller
+			cluster, err := controller.GetCluster(clusterID)
+			if err != nil {
+				exitError(err)
+			}
+
+			// Get the history of test runs for the cluster
+			records, err := cluster.GetHistory()
+			if err != nil {
+				exitError(err)
+			}
+			records = onit.FilterHistory(records, parseHistoryFilter(cmd))
+
+			// Write the records and logs to the destination
+			destination, _ := cmd.Flags().GetString("destination")
+			if status := cluster.ExportHistory(records, destination); status.Failed() {
+				exitStatus(status)
+			}
+		},
+	}
+
+	cmd.Flags().StringP("cluster", "c", getDefaultCluster(), "the cluster from which to export the history")
+	cmd.Flags().Lookup("cluster").Annotations = map[string][]string{
+		cobra.BashCompCustom: {"__onit_get_clusters"},
+	}
+	cmd.Flags().StringP("destination", "d", ".", "the destination to which to write the records and logs")
+	addHistoryFilterFlags(cmd)
+	return cmd
+}
@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
 	"text/tabwriter"
@@ -70,8 +71,35 @@ var (
 		# Get the history of test runs
 		onit get history
 
+		# Get the history of test runs including timings and per-test results
+		onit get history -o wide
+
+		# Watch the status of the nodes in the cluster
+		onit get nodes --watch
+
+		# Get the nodes in the cluster as JSON
+		onit get nodes -o json
+
+		# Get the IDs of the failed test runs
+		onit get history --status failed -o jsonpath='{range [*]}{.testId}{"\n"}{end}'
+
+		# Get the failed test runs of the last day
+		onit get history --status failed --since 24h
+
+		# Get the details of a single test run
+		onit get test <test-id>
+
 		# Get the list of installed apps
-		onit get apps`
+		onit get apps
+
+		# Get the links between the switches and hosts of a network
+		onit get network stratum-linear --links
+
+		# Get the addresses through which the cluster's services can be reached
+		onit get endpoints
+
+		# Get the device models loaded by onos-config
+		onit get model-plugins`
 )
 
 // getGetCommand returns a cobra "get" command to read test configurations
@@ -87,6 +115,7 @@ func getGetCommand(registry *runner.TestRegistry) *cobra.Command {
 	cmd.AddCommand(getGetPartitionCommand())
 	cmd.AddCommand(getGetSimulatorsCommand())
 	cmd.AddCommand(getGetNetworksCommand())
+	cmd.AddCommand(getGetNetworkCommand())
 	cmd.AddCommand(getGetClustersCommand())
 	cmd.AddCommand(getGetDevicePresetsCommand())
 	cmd.AddCommand(getGetStorePresetsCommand())
@@ -95,8 +124,11 @@ func getGetCommand(registry *runner.TestRegistry) *cobra.Command {
 	cmd.AddCommand(getGetBenchmarksCommand(registry))
 	cmd.AddCommand(getGetBenchmarkSuitesCommand(registry))
 	cmd.AddCommand(getGetHistoryCommand())
+	cmd.AddCommand(getGetTestCommand())
 	cmd.AddCommand(getGetLogsCommand())
 	cmd.AddCommand(getGetAppsCommand())
+	cmd.AddCommand(getGetEndpointsCommand())
+	cmd.AddCommand(getGetModelPluginsCommand())
 	return cmd
 }
 
@@ -140,17 +172,78 @@ func getGetNetworksCommand() *cobra.Command {
 			if err != nil {
 				exitError(err)
 			} else {
-				for _, name := range networks {
-					fmt.Println(name)
+				newPrinter(cmd).printNames(networks)
+			}
+		},
+	}
+
+	cmd.Flags().StringP("cluster", "c", getDefaultCluster(), "the cluster to query")
+	cmd.Flags().Lookup("cluster").Annotations = map[string][]string{
+		cobra.BashCompCustom: {"__onit_get_clusters"},
+	}
+	addOutputFlags(cmd)
+	return cmd
+}
+
+// getGetNetworkCommand returns a cobra command to get the switches or links of a network
+func getGetNetworkCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "network <name>",
+		Short: "Get the switches or links of a network",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			// Get the onit controller
+			controller, err := onit.NewController()
+			if err != nil {
+				exitError(err)
+			}
+
+			// Get the cluster ID
+			clusterID, err := cmd.Flags().GetString("cluster")
+			if err != nil {
+				exitError(err)
+			}
+
+			// Get the cluster controller
+			cluster, err := controller.GetCluster(clusterID)
+			if err != nil {
+				exitError(err)
+			}
+
+			network, err := cluster.GetNetwork(args[0])
+			if err != nil {
+				exitError(err)
+			}
+
+			p := newPrinter(cmd)
+			if links, _ := cmd.Flags().GetBool("links"); links {
+				t := newTable(
+					column{name: "SOURCE"},
+					column{name: "TARGET"})
+				for _, link := range network.Links {
+					t.addRow(link.Source, link.Target)
 				}
+				p.print(network.Links, t)
+				return
 			}
+
+			t := newTable(
+				column{name: "SWITCH"},
+				column{name: "DEVICE"},
+				column{name: "PORT"})
+			for _, s := range network.Switches {
+				t.addRow(s.Name, s.Device, s.Port)
+			}
+			p.print(network, t)
 		},
 	}
 
+	cmd.Flags().Bool("links", false, "list the links between the network's switches and hosts")
 	cmd.Flags().StringP("cluster", "c", getDefaultCluster(), "the cluster to query")
 	cmd.Flags().Lookup("cluster").Annotations = map[string][]string{
 		cobra.BashCompCustom: {"__onit_get_clusters"},
 	}
+	addOutputFlags(cmd)
 	return cmd
 }
 
@@ -178,25 +271,49 @@ func getGetSimulatorsCommand() *cobra.Command {
 				exitError(err)
 			}
 
+			// If watching the simulators, output the simulators' status as it changes
+			if watch, _ := cmd.Flags().GetBool("watch"); watch {
+				watchSimulators(newPrinter(cmd), cluster)
+				return
+			}
+
 			// Get the list of simulators and output
 			simulators, err := cluster.GetSimulators()
 			if err != nil {
 				exitError(err)
 			} else {
-				for _, name := range simulators {
-					fmt.Println(name)
-				}
+				newPrinter(cmd).printNames(simulators)
 			}
 		},
 	}
+	cmd.Flags().BoolP("watch", "w", false, "watch for changes to the simulators' status")
 
 	cmd.Flags().StringP("cluster", "c", getDefaultCluster(), "the cluster to query")
 	cmd.Flags().Lookup("cluster").Annotations = map[string][]string{
 		cobra.BashCompCustom: {"__onit_get_clusters"},
 	}
+	addOutputFlags(cmd)
 	return cmd
 }
 
+// watchSimulators outputs the status of the simulators in the given cluster as it changes
+func watchSimulators(p *printer, cluster *onit.ClusterController) {
+	simulators, err := cluster.GetSimulatorNodes()
+	if err != nil {
+		exitError(err)
+	}
+
+	ch := make(chan onit.NodeEvent)
+	if err := cluster.WatchSimulators(ch, make(chan struct{})); err != nil {
+		exitError(err)
+	}
+
+	w := p.watch(simulators, newNodesTable(simulators, false))
+	for event := range ch {
+		w.update(event.Node, nodeRow(event.Node, event.Type, false)...)
+	}
+}
+
 // getGetClustersCommand returns a cobra command to get a list of available test clusters
 func getGetClustersCommand() *cobra.Command {
 	cmd := &cobra.Command{
@@ -214,25 +331,40 @@ func getGetClustersCommand() *cobra.Command {
 			if err != nil {
 				exitError(err)
 			} else {
-				noHeaders, _ := cmd.Flags().GetBool("no-headers")
-				printClusters(clusters, !noHeaders)
+				printClusters(newPrinter(cmd), clusters)
 			}
 		},
 	}
-	cmd.Flags().Bool("no-headers", false, "whether to print column headers")
+	addOutputFlags(cmd)
 	return cmd
 }
 
-func printClusters(clusters map[string]*onit.ClusterConfig, includeHeaders bool) {
-	writer := new(tabwriter.Writer)
-	writer.Init(os.Stdout, 0, 0, 3, ' ', tabwriter.FilterHTML)
-	if includeHeaders {
-		fmt.Fprintln(writer, "ID\tSIZE\tPARTITIONS")
+// printClusters prints the given clusters
+func printClusters(p *printer, clusters map[string]*onit.ClusterConfig) {
+	ids := make([]string, 0, len(clusters))
+	for id := range clusters {
+		ids = append(ids, id)
 	}
-	for id, config := range clusters {
-		fmt.Fprintln(writer, fmt.Sprintf("%s\t%d\t%d\t%d", id, config.ConfigNodes, config.TopoNodes, config.Partitions))
+	sort.Strings(ids)
+
+	t := newTable(
+		column{name: "ID"},
+		column{name: "CONFIG NODES"},
+		column{name: "TOPO NODES"},
+		column{name: "PARTITIONS"},
+		column{name: "PARTITION SIZE", wide: true},
+		column{name: "PRESET", wide: true},
+		column{name: "REGISTRY", wide: true},
+		column{name: "EXPOSURE", wide: true})
+	for _, id := range ids {
+		config := clusters[id]
+		exposure := config.Exposure
+		if exposure == "" {
+			exposure = onit.ExposeIngress
+		}
+		t.addRow(id, config.ConfigNodes, config.TopoNodes, config.Partitions, config.PartitionSize, config.Preset, config.Registry, exposure)
 	}
-	writer.Flush()
+	p.print(clusters, t)
 }
 
 // getGetDevicePresetsCommand returns a cobra command to get a list of available device simulator configurations
@@ -289,26 +421,61 @@ func getGetPartitionsCommand() *cobra.Command {
 			partitions, err := cluster.GetPartitions()
 			if err != nil {
 				exitError(err)
+			} else if watch, _ := cmd.Flags().GetBool("watch"); watch {
+				watchPartitions(newPrinter(cmd), cluster, partitions)
 			} else {
-				printPartitions(partitions)
+				printPartitions(newPrinter(cmd), partitions)
 			}
 		},
 	}
+	cmd.Flags().BoolP("watch", "w", false, "watch for changes to the partitions")
 	cmd.Flags().StringP("cluster", "c", getDefaultCluster(), "the cluster to query")
 	cmd.Flags().Lookup("cluster").Annotations = map[string][]string{
 		cobra.BashCompCustom: {"__onit_get_clusters"},
 	}
+	addOutputFlags(cmd)
 	return cmd
 }
 
-func printPartitions(partitions []onit.PartitionInfo) {
-	writer := new(tabwriter.Writer)
-	writer.Init(os.Stdout, 0, 0, 3, ' ', tabwriter.FilterHTML)
-	fmt.Fprintln(writer, "ID\tGROUP\tNODES")
+// printPartitions prints the given partitions
+func printPartitions(p *printer, partitions []onit.PartitionInfo) {
+	p.print(partitions, newPartitionsTable(partitions))
+}
+
+// watchPartitions prints the given partitions and outputs changes to the partitions in the given cluster
+func watchPartitions(p *printer, cluster *onit.ClusterController, partitions []onit.PartitionInfo) {
+	ch := make(chan onit.PartitionEvent)
+	if err := cluster.WatchPartitions(ch, make(chan struct{})); err != nil {
+		exitError(err)
+	}
+
+	w := p.watch(partitions, newPartitionsTable(partitions))
+	for event := range ch {
+		w.update(event.Partition, partitionRow(event.Partition, event.Type)...)
+	}
+}
+
+// newPartitionsTable returns a table of the given partitions
+func newPartitionsTable(partitions []onit.PartitionInfo) *table {
+	t := newTable(
+		column{name: "ID"},
+		column{name: "GROUP"},
+		column{name: "NODES"},
+		column{name: "READY"},
+		column{name: "SIZE", wide: true})
 	for _, partition := range partitions {
-		fmt.Fprintln(writer, fmt.Sprintf("%d\t%s\t%s", partition.Partition, partition.Group, strings.Join(partition.Nodes, ",")))
+		t.addRow(partitionRow(partition, onit.EventAdded)...)
 	}
-	writer.Flush()
+	return t
+}
+
+// partitionRow returns the row of a partitions table for the given partition
+func partitionRow(partition onit.PartitionInfo, eventType onit.EventType) []interface{} {
+	ready := fmt.Sprintf("%d/%d", partition.Ready, len(partition.Nodes))
+	if eventType == onit.EventDeleted {
+		ready = string(onit.EventDeleted)
+	}
+	return []interface{}{partition.Partition, partition.Group, strings.Join(partition.Nodes, ","), ready, len(partition.Nodes)}
 }
 
 // getGetAppsCommand returns a cobra command to get the list of apps deployed in the current cluster context
@@ -340,10 +507,63 @@ func getGetAppsCommand() *cobra.Command {
 			if err != nil {
 				exitError(err)
 			} else {
-				for _, name := range apps {
-					fmt.Println(name)
+				newPrinter(cmd).printNames(apps)
+			}
+		},
+	}
+
+	cmd.Flags().StringP("cluster", "c", getDefaultCluster(), "the cluster to query")
+	cmd.Flags().Lookup("cluster").Annotations = map[string][]string{
+		cobra.BashCompCustom: {"__onit_get_clusters"},
+	}
+	addOutputFlags(cmd)
+	return cmd
+}
+
+// getGetEndpointsCommand returns a cobra command to get the addresses of the cluster's services
+func getGetEndpointsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "endpoints",
+		Short: "Get the addresses through which the cluster's gNMI, topo and GUI services can be reached",
+		Run: func(cmd *cobra.Command, args []string) {
+			// Get the onit controller
+			controller, err := onit.NewController()
+			if err != nil {
+				exitError(err)
+			}
+
+			// Get the cluster ID
+			clusterID, err := cmd.Flags().GetString("cluster")
+			if err != nil {
+				exitError(err)
+			}
+
+			// Get the cluster controller
+			cluster, err := controller.GetCluster(clusterID)
+			if err != nil {
+				exitError(err)
+			}
+
+			// Get the list of endpoints and output
+			endpoints, err := cluster.GetEndpoints()
+			if err != nil {
+				exitError(err)
+			}
+
+			t := newTable(
+				column{name: "NAME"},
+				column{name: "ADDRESS"},
+				column{name: "HOST"},
+				column{name: "SERVICE", wide: true},
+				column{name: "EXPOSURE", wide: true})
+			for _, endpoint := range endpoints {
+				host := endpoint.Host
+				if host == "" {
+					host = "*"
 				}
+				t.addRow(endpoint.Name, endpoint.Address, host, endpoint.Service, endpoint.Exposure)
 			}
+			newPrinter(cmd).print(endpoints, t)
 		},
 	}
 
@@ -351,6 +571,56 @@ func getGetAppsCommand() *cobra.Command {
 	cmd.Flags().Lookup("cluster").Annotations = map[string][]string{
 		cobra.BashCompCustom: {"__onit_get_clusters"},
 	}
+	addOutputFlags(cmd)
+	return cmd
+}
+
+// getGetModelPluginsCommand returns a cobra command to get the device models loaded by onos-config
+func getGetModelPluginsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "model-plugins",
+		Short: "Get the device models loaded by onos-config",
+		Run: func(cmd *cobra.Command, args []string) {
+			// Get the onit controller
+			controller, err := onit.NewController()
+			if err != nil {
+				exitError(err)
+			}
+
+			// Get the cluster ID
+			clusterID, err := cmd.Flags().GetString("cluster")
+			if err != nil {
+				exitError(err)
+			}
+
+			// Get the cluster controller
+			cluster, err := controller.GetCluster(clusterID)
+			if err != nil {
+				exitError(err)
+			}
+
+			// Get the list of models from the onos-config gNMI capabilities and output
+			models, err := cluster.GetModels()
+			if err != nil {
+				exitError(err)
+			}
+
+			t := newTable(
+				column{name: "NAME"},
+				column{name: "VERSION"},
+				column{name: "ORGANIZATION", wide: true})
+			for _, model := range models {
+				t.addRow(model.Name, model.Version, model.Organization)
+			}
+			newPrinter(cmd).print(models, t)
+		},
+	}
+
+	cmd.Flags().StringP("cluster", "c", getDefaultCluster(), "the cluster to query")
+	cmd.Flags().Lookup("cluster").Annotations = map[string][]string{
+		cobra.BashCompCustom: {"__onit_get_clusters"},
+	}
+	addOutputFlags(cmd)
 	return cmd
 }
 
@@ -390,7 +660,7 @@ func getGetPartitionCommand() *cobra.Command {
 			if err != nil {
 				exitError(err)
 			} else {
-				printNodes(nodes, true)
+				printNodes(newPrinter(cmd), nodes)
 			}
 		},
 	}
@@ -398,6 +668,7 @@ func getGetPartitionCommand() *cobra.Command {
 	cmd.Flags().Lookup("cluster").Annotations = map[string][]string{
 		cobra.BashCompCustom: {"__onit_get_clusters"},
 	}
+	addOutputFlags(cmd)
 	return cmd
 }
 
@@ -431,75 +702,90 @@ func getGetNodesCommand() *cobra.Command {
 			}
 
 			// Get the list of nodes and output
-			if strings.Compare(nodeType, string(onit.OnosAll)) == 0 {
-				nodes, err := cluster.GetNodes()
-				if err != nil {
-					exitError(err)
-				} else {
-					noHeaders, _ := cmd.Flags().GetBool("no-headers")
-					printNodes(nodes, !noHeaders)
-				}
-			} else if strings.Compare(nodeType, string(onit.OnosConfig)) == 0 {
-				nodes, err := cluster.GetOnosConfigNodes()
-				if err != nil {
-					exitError(err)
-				} else {
-					noHeaders, _ := cmd.Flags().GetBool("no-headers")
-					printNodes(nodes, !noHeaders)
-				}
-
-			} else if strings.Compare(nodeType, string(onit.OnosTopo)) == 0 {
-				nodes, err := cluster.GetOnosTopoNodes()
-				if err != nil {
-					exitError(err)
-				} else {
-					noHeaders, _ := cmd.Flags().GetBool("no-headers")
-					printNodes(nodes, !noHeaders)
-				}
-
-			} else if strings.Compare(nodeType, string(onit.OnosCli)) == 0 {
-				nodes, err := cluster.GetOnosCliNodes()
-				if err != nil {
-					exitError(err)
-				} else {
-					noHeaders, _ := cmd.Flags().GetBool("no-headers")
-					printNodes(nodes, !noHeaders)
-				}
+			var nodes []onit.NodeInfo
+			if onit.NodeType(nodeType) == onit.OnosAll {
+				nodes, err = cluster.GetNodes()
+			} else {
+				nodes, err = cluster.GetComponentNodes(nodeType)
+			}
+			if err != nil {
+				exitError(err)
+			} else if watch, _ := cmd.Flags().GetBool("watch"); watch {
+				watchNodes(newPrinter(cmd), cluster, onit.NodeType(nodeType), nodes)
+			} else {
+				printNodes(newPrinter(cmd), nodes)
 			}
 		},
 	}
 	cmd.Flags().StringP("cluster", "c", getDefaultCluster(), "the cluster to query")
-	cmd.Flags().StringP("type", "t", "all", "To get list of nodes based on their types")
+	cmd.Flags().StringP("type", "t", "all", "the type of nodes to list (all or a component, e.g. config, topo, gui, cli)")
+	cmd.Flags().BoolP("watch", "w", false, "watch for changes to the nodes' status")
 	cmd.Flags().Lookup("cluster").Annotations = map[string][]string{
 		cobra.BashCompCustom: {"__onit_get_clusters"},
 	}
-	cmd.Flags().Bool("no-headers", false, "whether to print column headers")
+	addOutputFlags(cmd)
 	return cmd
 }
 
-func printNodes(nodes []onit.NodeInfo, includeHeaders bool) {
-	writer := new(tabwriter.Writer)
-	writer.Init(os.Stdout, 0, 0, 3, ' ', tabwriter.FilterHTML)
-	if includeHeaders {
-		fmt.Fprintln(writer, "ID\tTYPE\tSTATUS")
+// printNodes prints the given nodes
+func printNodes(p *printer, nodes []onit.NodeInfo) {
+	p.print(nodes, newNodesTable(nodes, true))
+}
+
+// watchNodes prints the given nodes and outputs changes to the nodes of the given type in the given cluster
+func watchNodes(p *printer, cluster *onit.ClusterController, nodeType onit.NodeType, nodes []onit.NodeInfo) {
+	ch := make(chan onit.NodeEvent)
+	if err := cluster.WatchNodes(nodeType, ch, make(chan struct{})); err != nil {
+		exitError(err)
+	}
+
+	w := p.watch(nodes, newNodesTable(nodes, true))
+	for event := range ch {
+		w.update(event.Node, nodeRow(event.Node, event.Type, true)...)
+	}
+}
+
+// newNodesTable returns a table of the given nodes
+func newNodesTable(nodes []onit.NodeInfo, includeType bool) *table {
+	columns := []column{{name: "ID"}}
+	if includeType {
+		columns = append(columns, column{name: "TYPE"})
 	}
+	columns = append(columns,
+		column{name: "STATUS"},
+		column{name: "READY"},
+		column{name: "RESTARTS", wide: true})
+	t := newTable(columns...)
 	for _, node := range nodes {
-		fmt.Fprintln(writer, fmt.Sprintf("%s\t%s\t%s", node.ID, node.Type, node.Status))
+		t.addRow(nodeRow(node, onit.EventAdded, includeType)...)
 	}
-	writer.Flush()
+	return t
+}
+
+// nodeRow returns the row of a nodes table for the given node
+func nodeRow(node onit.NodeInfo, eventType onit.EventType, includeType bool) []interface{} {
+	status := string(node.Status)
+	if eventType == onit.EventDeleted {
+		status = string(onit.EventDeleted)
+	}
+	row := []interface{}{node.ID}
+	if includeType {
+		row = append(row, node.Type)
+	}
+	return append(row, status, node.Ready, node.Restarts)
 }
 
 // getGetTestsCommand returns a cobra command to get a list of available tests
 func getGetTestsCommand(registry *runner.TestRegistry) *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "tests",
 		Short: "Get a list of integration tests",
 		Run: func(cmd *cobra.Command, args []string) {
-			for _, name := range registry.GetTestNames() {
-				fmt.Println(name)
-			}
+			newPrinter(cmd).printNames(registry.GetTestNames())
 		},
 	}
+	addOutputFlags(cmd)
+	return cmd
 }
 
 // getGetTestsCommand returns a cobra command to get a list of available tests
@@ -509,40 +795,40 @@ func getGetTestSuitesCommand(registry *runner.TestRegistry) *cobra.Command {
 		Aliases: []string{"suites"},
 		Short:   "Get a list of integration testing suites",
 		Run: func(cmd *cobra.Command, args []string) {
-			noHeaders, _ := cmd.Flags().GetBool("no-headers")
-			printTestSuites(registry, !noHeaders)
+			printTestSuites(newPrinter(cmd), registry)
 		},
 	}
 
-	cmd.Flags().Bool("no-headers", false, "whether to print column headers")
+	addOutputFlags(cmd)
 	return cmd
 }
 
-// PrintTestSuites prints test suites in a table
-func printTestSuites(registry *runner.TestRegistry, includeHeaders bool) {
-	writer := new(tabwriter.Writer)
-	writer.Init(os.Stdout, 0, 0, 3, ' ', tabwriter.FilterHTML)
-	if includeHeaders {
-		fmt.Fprintln(writer, "SUITE\tTESTS")
-	}
-	for name, suite := range registry.TestSuites {
-		fmt.Fprintln(writer, fmt.Sprintf("%s\t%s", name, strings.Join(suite.GetTestNames(), ", ")))
+// printTestSuites prints test suites in a table
+func printTestSuites(p *printer, registry *runner.TestRegistry) {
+	suites := make(map[string][]string)
+	t := newTable(
+		column{name: "SUITE"},
+		column{name: "TESTS"})
+	for _, name := range registry.GetTestSuiteNames() {
+		suite := registry.TestSuites[name]
+		suites[name] = suite.GetTestNames()
+		t.addRow(name, strings.Join(suites[name], ", "))
 	}
-	writer.Flush()
+	p.print(suites, t)
 }
 
 // getGetBenchmarksCommand returns a cobra command to get a list of available tests
 func getGetBenchmarksCommand(registry *runner.TestRegistry) *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:     "benchmarks",
 		Aliases: []string{"bench", "benchmark"},
 		Short:   "Get a list of benchmarks",
 		Run: func(cmd *cobra.Command, args []string) {
-			for _, name := range registry.GetBenchmarkNames() {
-				fmt.Println(name)
-			}
+			newPrinter(cmd).printNames(registry.GetBenchmarkNames())
 		},
 	}
+	addOutputFlags(cmd)
+	return cmd
 }
 
 // getGetBenchmarkSuitesCommand returns a cobra command to get a list of available tests
@@ -552,26 +838,26 @@ func getGetBenchmarkSuitesCommand(registry *runner.TestRegistry) *cobra.Command
 		Aliases: []string{"benchmark-suites"},
 		Short:   "Get a list of benchmark suites",
 		Run: func(cmd *cobra.Command, args []string) {
-			noHeaders, _ := cmd.Flags().GetBool("no-headers")
-			printBenchSuites(registry, !noHeaders)
+			printBenchSuites(newPrinter(cmd), registry)
 		},
 	}
 
-	cmd.Flags().Bool("no-headers", false, "whether to print column headers")
+	addOutputFlags(cmd)
 	return cmd
 }
 
 // printBenchSuites prints benchmark suites in a table
-func printBenchSuites(registry *runner.TestRegistry, includeHeaders bool) {
-	writer := new(tabwriter.Writer)
-	writer.Init(os.Stdout, 0, 0, 3, ' ', tabwriter.FilterHTML)
-	if includeHeaders {
-		fmt.Fprintln(writer, "SUITE\tBENCHMARKS")
-	}
-	for name, suite := range registry.BenchSuites {
-		fmt.Fprintln(writer, fmt.Sprintf("%s\t%s", name, strings.Join(suite.GetBenchNames(), ", ")))
+func printBenchSuites(p *printer, registry *runner.TestRegistry) {
+	suites := make(map[string][]string)
+	t := newTable(
+		column{name: "SUITE"},
+		column{name: "BENCHMARKS"})
+	for _, name := range registry.GetBenchSuiteNames() {
+		suite := registry.BenchSuites[name]
+		suites[name] = suite.GetBenchNames()
+		t.addRow(name, strings.Join(suites[name], ", "))
 	}
-	writer.Flush()
+	p.print(suites, t)
 }
 
 // getGetHistoryCommand returns a cobra command to get the history of tests
@@ -603,33 +889,237 @@ func getGetHistoryCommand() *cobra.Command {
 			if err != nil {
 				exitError(err)
 			}
-
-			printHistory(records)
+			filter := parseHistoryFilter(cmd)
+			records = onit.FilterHistory(records, filter)
+			if watch, _ := cmd.Flags().GetBool("watch"); watch {
+				watchHistory(newPrinter(cmd), cluster, records, filter)
+			} else {
+				printHistory(newPrinter(cmd), records)
+			}
 		},
 	}
+	cmd.Flags().BoolP("watch", "w", false, "watch for changes to the status of test runs")
 
 	cmd.Flags().StringP("cluster", "c", getDefaultCluster(), "the cluster for which to load the history")
 	cmd.Flags().Lookup("cluster").Annotations = map[string][]string{
 		cobra.BashCompCustom: {"__onit_get_clusters"},
 	}
+	addOutputFlags(cmd)
+	addHistoryFilterFlags(cmd)
 	return cmd
 }
 
-// printHistory prints a test history in table format
-func printHistory(records []onit.TestRecord) {
-	writer := new(tabwriter.Writer)
-	writer.Init(os.Stdout, 0, 0, 3, ' ', tabwriter.FilterHTML)
-	fmt.Fprintln(writer, "ID\tTESTS\tSTATUS\tEXIT CODE\tMESSAGE")
+// addHistoryFilterFlags adds flags for filtering the test history to the given command
+func addHistoryFilterFlags(cmd *cobra.Command) {
+	cmd.Flags().String("status", "", "only include test runs with the given status (running, passed, failed)")
+	cmd.Flags().String("type", "", "only include test runs of the given type (test, test-suite, bench, bench-suite)")
+	cmd.Flags().String("test", "", "only include test runs of the given test or suite")
+	cmd.Flags().Duration("since", 0, "only include test runs started within a relative duration like 5s, 2m, or 3h")
+}
+
+// parseHistoryFilter parses the test history filter from the given command's flags
+func parseHistoryFilter(cmd *cobra.Command) onit.HistoryFilter {
+	status, _ := cmd.Flags().GetString("status")
+	testType, _ := cmd.Flags().GetString("type")
+	test, _ := cmd.Flags().GetString("test")
+	since, _ := cmd.Flags().GetDuration("since")
+	return onit.HistoryFilter{
+		Status: onit.TestStatus(status),
+		Type:   onit.TestType(testType),
+		Test:   test,
+		Since:  since,
+	}
+}
+
+// printHistory prints a test history
+func printHistory(p *printer, records []onit.TestRecord) {
+	p.print(records, newHistoryTable(records))
+}
+
+// watchHistory prints the given test history and outputs changes to the test runs in the given cluster
+func watchHistory(p *printer, cluster *onit.ClusterController, records []onit.TestRecord, filter onit.HistoryFilter) {
+	ch := make(chan onit.TestEvent)
+	if err := cluster.WatchHistory(ch, make(chan struct{})); err != nil {
+		exitError(err)
+	}
+
+	w := p.watch(records, newHistoryTable(records))
+	for event := range ch {
+		if filter.Matches(event.Record) {
+			w.update(event.Record, historyRow(event.Record, event.Type)...)
+		}
+	}
+}
+
+// newHistoryTable returns a table of the given test records
+func newHistoryTable(records []onit.TestRecord) *table {
+	t := newTable(
+		column{name: "ID"},
+		column{name: "TYPE", wide: true},
+		column{name: "TESTS"},
+		column{name: "STATUS"},
+		column{name: "EXIT CODE"},
+		column{name: "STARTED", wide: true},
+		column{name: "DURATION", wide: true},
+		column{name: "PASSED", wide: true},
+		column{name: "FAILED", wide: true},
+		column{name: "SKIPPED", wide: true},
+		column{name: "MESSAGE"})
 	for _, record := range records {
-		var args string
-		if len(record.Args) > 0 {
-			args = strings.Join(record.Args, ",")
-		} else {
-			args = "*"
+		t.addRow(historyRow(record, onit.EventAdded)...)
+	}
+	return t
+}
+
+// historyRow returns the row of a history table for the given test record
+func historyRow(record onit.TestRecord, eventType onit.EventType) []interface{} {
+	var args string
+	if len(record.Args) > 0 {
+		args = strings.Join(record.Args, ",")
+	} else {
+		args = "*"
+	}
+	status := string(record.Status)
+	if eventType == onit.EventDeleted {
+		status = string(onit.EventDeleted)
+	}
+	passed, failed, skipped := countResults(record.Results)
+	return []interface{}{record.TestID, record.Type, args, status, record.ExitCode,
+		formatTime(record.StartTime), formatDuration(record.Duration), passed, failed, skipped, record.Message}
+}
+
+// countResults returns the number of passed, failed and skipped tests in the given results
+func countResults(results []onit.TestResult) (int, int, int) {
+	passed, failed, skipped := 0, 0, 0
+	for _, result := range results {
+		switch result.Status {
+		case onit.TestPassed:
+			passed++
+		case onit.TestFailed:
+			failed++
+		case onit.TestSkipped:
+			skipped++
 		}
-		fmt.Fprintln(writer, fmt.Sprintf("%s\t%s\t%s\t%d\t%s", record.TestID, args, record.Status, record.ExitCode, record.Message))
 	}
+	return passed, failed, skipped
+}
+
+// formatTime formats the given time for output, returning an empty string for the zero time
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// formatDuration formats the given duration for output
+func formatDuration(d time.Duration) string {
+	if d == 0 {
+		return ""
+	}
+	return d.Round(time.Millisecond).String()
+}
+
+// getGetTestCommand returns a cobra command to get the details of a single test run
+func getGetTestCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "test <id>",
+		Short: "Get the details of a test run",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			// Get the onit controller
+			controller, err := onit.NewController()
+			if err != nil {
+				exitError(err)
+			}
+
+			// Get the cluster ID
+			clusterID, err := cmd.Flags().GetString("cluster")
+			if err != nil {
+				exitError(err)
+			}
+
+			// Get the cluster controller
+			cluster, err := controller.GetCluster(clusterID)
+			if err != nil {
+				exitError(err)
+			}
+
+			// Get the record for the test run
+			record, err := cluster.GetRecord(args[0])
+			if err != nil {
+				exitError(err)
+			}
+
+			p := newPrinter(cmd)
+			if p.isTable() {
+				printRecord(record)
+			} else {
+				p.print(record, nil)
+			}
+		},
+	}
+
+	cmd.Flags().StringP("cluster", "c", getDefaultCluster(), "the cluster for which to load the test run")
+	cmd.Flags().Lookup("cluster").Annotations = map[string][]string{
+		cobra.BashCompCustom: {"__onit_get_clusters"},
+	}
+	addOutputFlags(cmd)
+	return cmd
+}
+
+// printRecord prints the details of a single test run
+func printRecord(record onit.TestRecord) {
+	writer := new(tabwriter.Writer)
+	writer.Init(os.Stdout, 0, 0, 3, ' ', tabwriter.FilterHTML)
+	fmt.Fprintln(writer, fmt.Sprintf("ID:\t%s", record.TestID))
+	fmt.Fprintln(writer, fmt.Sprintf("TYPE:\t%s", record.Type))
+	fmt.Fprintln(writer, fmt.Sprintf("ARGS:\t%s", strings.Join(record.Args, " ")))
+	fmt.Fprintln(writer, fmt.Sprintf("STATUS:\t%s", record.Status))
+	fmt.Fprintln(writer, fmt.Sprintf("EXIT CODE:\t%d", record.ExitCode))
+	fmt.Fprintln(writer, fmt.Sprintf("MESSAGE:\t%s", record.Message))
+	fmt.Fprintln(writer, fmt.Sprintf("STARTED:\t%s", formatTime(record.StartTime)))
+	fmt.Fprintln(writer, fmt.Sprintf("FINISHED:\t%s", formatTime(record.EndTime)))
+	fmt.Fprintln(writer, fmt.Sprintf("DURATION:\t%s", formatDuration(record.Duration)))
+	fmt.Fprintln(writer, fmt.Sprintf("DEVICES:\t%s", strings.Join(record.Devices, ",")))
 	writer.Flush()
+
+	if len(record.Results) > 0 {
+		fmt.Println()
+		writer.Init(os.Stdout, 0, 0, 3, ' ', tabwriter.FilterHTML)
+		fmt.Fprintln(writer, "TEST\tSTATUS\tDURATION")
+		for _, result := range record.Results {
+			fmt.Fprintln(writer, fmt.Sprintf("%s\t%s\t%s", result.Name, result.Status, result.Duration))
+		}
+		writer.Flush()
+	}
+
+	if len(record.Images) > 0 {
+		fmt.Println()
+		writer.Init(os.Stdout, 0, 0, 3, ' ', tabwriter.FilterHTML)
+		fmt.Fprintln(writer, "IMAGE\tDIGEST")
+		for _, image := range record.Images {
+			fmt.Fprintln(writer, fmt.Sprintf("%s\t%s", image.Image, image.Digest))
+		}
+		writer.Flush()
+	}
+
+	if record.Config != nil {
+		fmt.Println()
+		writer.Init(os.Stdout, 0, 0, 3, ' ', tabwriter.FilterHTML)
+		fmt.Fprintln(writer, "CONFIG\tVALUE")
+		fmt.Fprintln(writer, fmt.Sprintf("registry\t%s", record.Config.Registry))
+		fmt.Fprintln(writer, fmt.Sprintf("preset\t%s", record.Config.Preset))
+		fmt.Fprintln(writer, fmt.Sprintf("pullPolicy\t%s", record.Config.PullPolicy))
+		fmt.Fprintln(writer, fmt.Sprintf("configNodes\t%d", record.Config.ConfigNodes))
+		fmt.Fprintln(writer, fmt.Sprintf("topoNodes\t%d", record.Config.TopoNodes))
+		fmt.Fprintln(writer, fmt.Sprintf("partitions\t%d", record.Config.Partitions))
+		fmt.Fprintln(writer, fmt.Sprintf("partitionSize\t%d", record.Config.PartitionSize))
+		for name, tag := range record.Config.ImageTags {
+			fmt.Fprintln(writer, fmt.Sprintf("imageTags.%s\t%s", name, tag))
+		}
+		writer.Flush()
+	}
 }
 
 // getGetLogsCommand returns a cobra command to output the logs for a specific resource
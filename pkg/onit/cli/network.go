@@ -0,0 +1,128 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"fmt"
+
+	"github.com/onosproject/onos-test/pkg/onit"
+	"github.com/onosproject/onos-test/pkg/onit/mininet"
+	"github.com/spf13/cobra"
+)
+
+var (
+	networkExample = `
+		# Bring down the link between switches s1 and s2 of a network
+		onit network link stratum-linear s1 s2 down
+
+		# Bring the link back up
+		onit network link stratum-linear s1 s2 up
+
+		# Stop switch s2 of a network
+		onit network switch stratum-linear s2 stop
+
+		# Restart the switch
+		onit network switch stratum-linear s2 start`
+)
+
+// getNetworkCommand returns a cobra "network" command for changing the state of a network's links and switches
+func getNetworkCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "network {link,switch} [args]",
+		Short:   "Change the state of the links and switches of a network",
+		Example: networkExample,
+	}
+	cmd.AddCommand(getNetworkLinkCommand())
+	cmd.AddCommand(getNetworkSwitchCommand())
+	return cmd
+}
+
+// getNetworkLinkCommand returns a cobra command for bringing a link of a network up or down
+func getNetworkLinkCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "link <network> <node> <node> {up,down}",
+		Short: "Bring the link between two nodes of a network up or down",
+		Args:  cobra.ExactArgs(4),
+		Run: func(cmd *cobra.Command, args []string) {
+			state, err := mininet.ParseLinkState(args[3])
+			if err != nil {
+				exitError(err)
+			}
+
+			cluster := getNetworkCluster(cmd)
+			result, status := cluster.SetNetworkLink(args[0], args[1], args[2], state)
+			if status.Failed() {
+				exitStatus(status)
+			}
+			fmt.Printf("link %s-%s is %s\n", args[1], args[2], result)
+		},
+	}
+	addNetworkClusterFlag(cmd)
+	return cmd
+}
+
+// getNetworkSwitchCommand returns a cobra command for starting or stopping a switch of a network
+func getNetworkSwitchCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "switch <network> <switch> {start,stop}",
+		Short: "Start or stop a switch of a network",
+		Args:  cobra.ExactArgs(3),
+		Run: func(cmd *cobra.Command, args []string) {
+			action, err := mininet.ParseSwitchAction(args[2])
+			if err != nil {
+				exitError(err)
+			}
+
+			cluster := getNetworkCluster(cmd)
+			result, status := cluster.SetNetworkSwitch(args[0], args[1], action)
+			if status.Failed() {
+				exitStatus(status)
+			}
+			fmt.Printf("switch %s is %s\n", args[1], result)
+		},
+	}
+	addNetworkClusterFlag(cmd)
+	return cmd
+}
+
+// getNetworkCluster returns the controller for the cluster in which a network command operates
+func getNetworkCluster(cmd *cobra.Command) *onit.ClusterController {
+	// Get the onit controller
+	controller, err := onit.NewController()
+	if err != nil {
+		exitError(err)
+	}
+
+	// Get the cluster ID
+	clusterID, err := cmd.Flags().GetString("cluster")
+	if err != nil {
+		exitError(err)
+	}
+
+	// Get the cluster controller
+	cluster, err := controller.GetCluster(clusterID)
+	if err != nil {
+		exitError(err)
+	}
+	return cluster
+}
+
+// addNetworkClusterFlag adds the cluster flag to a network command
+func addNetworkClusterFlag(cmd *cobra.Command) {
+	cmd.Flags().StringP("cluster", "c", getDefaultCluster(), "the cluster in which the network is running")
+	cmd.Flags().Lookup("cluster").Annotations = map[string][]string{
+		cobra.BashCompCustom: {"__onit_get_clusters"},
+	}
+}
@@ -0,0 +1,295 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ghodss/yaml"
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// outputFormat is the format in which the get commands print objects
+type outputFormat string
+
+const (
+	tableOutput    outputFormat = ""
+	wideOutput     outputFormat = "wide"
+	jsonOutput     outputFormat = "json"
+	yamlOutput     outputFormat = "yaml"
+	jsonPathOutput outputFormat = "jsonpath"
+)
+
+// column is a column in a table of objects
+type column struct {
+	name string
+	wide bool
+}
+
+// table is a table of objects to print in the table and wide output formats
+type table struct {
+	columns []column
+	rows    [][]string
+}
+
+// newTable returns a new table with the given columns
+func newTable(columns ...column) *table {
+	return &table{
+		columns: columns,
+		rows:    make([][]string, 0),
+	}
+}
+
+// addRow adds a row to the table. A value must be provided for every column, including wide columns.
+func (t *table) addRow(values ...interface{}) {
+	row := make([]string, len(values))
+	for i, value := range values {
+		row[i] = fmt.Sprint(value)
+	}
+	t.rows = append(t.rows, row)
+}
+
+// printer prints objects in the output format selected by the command's flags
+type printer struct {
+	out       io.Writer
+	format    outputFormat
+	jsonPath  *jsonpath.JSONPath
+	noHeaders bool
+}
+
+// addOutputFlags adds the flags for selecting the output format to the given command
+func addOutputFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP("output", "o", "", "the output format (json, yaml, wide, jsonpath=<expr>)")
+	cmd.Flags().Bool("no-headers", false, "whether to print column headers")
+}
+
+// newPrinter returns a printer for the output format selected by the given command's flags
+func newPrinter(cmd *cobra.Command) *printer {
+	output, _ := cmd.Flags().GetString("output")
+	noHeaders, _ := cmd.Flags().GetBool("no-headers")
+	p := &printer{
+		out:       os.Stdout,
+		noHeaders: noHeaders,
+	}
+
+	format := output
+	if i := strings.Index(output, "="); i >= 0 {
+		format = output[:i]
+	}
+
+	switch outputFormat(format) {
+	case tableOutput, wideOutput, jsonOutput, yamlOutput:
+		p.format = outputFormat(format)
+	case jsonPathOutput:
+		expr := strings.TrimPrefix(output, format)
+		if !strings.HasPrefix(expr, "=") || len(expr) == 1 {
+			exitError(fmt.Errorf("a template must be provided with the jsonpath output format, e.g. jsonpath={.ID}"))
+		}
+		expr = expr[1:]
+		if !strings.Contains(expr, "{") {
+			expr = fmt.Sprintf("{%s}", expr)
+		}
+		p.format = jsonPathOutput
+		p.jsonPath = jsonpath.New("output")
+		if err := p.jsonPath.Parse(expr); err != nil {
+			exitError(err)
+		}
+	default:
+		exitError(fmt.Errorf("unknown output format %s; must be one of json, yaml, wide or jsonpath=<expr>", output))
+	}
+	return p
+}
+
+// print prints the given object, using the given table for the table and wide output formats
+func (p *printer) print(obj interface{}, t *table) {
+	switch p.format {
+	case jsonOutput:
+		bytes, err := json.MarshalIndent(obj, "", "  ")
+		if err != nil {
+			exitError(err)
+		}
+		fmt.Fprintln(p.out, string(bytes))
+	case yamlOutput:
+		bytes, err := yaml.Marshal(obj)
+		if err != nil {
+			exitError(err)
+		}
+		fmt.Fprint(p.out, string(bytes))
+	case jsonPathOutput:
+		// Convert the object to its JSON representation to ensure field names match the json output
+		bytes, err := json.Marshal(obj)
+		if err != nil {
+			exitError(err)
+		}
+		var data interface{}
+		if err := json.Unmarshal(bytes, &data); err != nil {
+			exitError(err)
+		}
+		if err := p.jsonPath.Execute(p.out, data); err != nil {
+			exitError(err)
+		}
+		fmt.Fprintln(p.out)
+	default:
+		p.printTable(t)
+	}
+}
+
+// isTable returns a boolean indicating whether objects are printed in a table
+func (p *printer) isTable() bool {
+	return p.format == tableOutput || p.format == wideOutput
+}
+
+// printNames prints the given list of names, one per line in the table and wide output formats
+func (p *printer) printNames(names []string) {
+	if !p.isTable() {
+		p.print(names, nil)
+		return
+	}
+	for _, name := range names {
+		fmt.Fprintln(p.out, name)
+	}
+}
+
+// printTable prints the given table, including wide columns only in the wide output format
+func (p *printer) printTable(t *table) {
+	writer := new(tabwriter.Writer)
+	writer.Init(p.out, 0, 0, 3, ' ', tabwriter.FilterHTML)
+	if !p.noHeaders {
+		headers := make([]string, 0, len(t.columns))
+		for _, column := range t.columns {
+			if !column.wide || p.format == wideOutput {
+				headers = append(headers, column.name)
+			}
+		}
+		fmt.Fprintln(writer, strings.Join(headers, "\t"))
+	}
+	for _, row := range t.rows {
+		values := make([]string, 0, len(row))
+		for i, value := range row {
+			if i < len(t.columns) && (!t.columns[i].wide || p.format == wideOutput) {
+				values = append(values, value)
+			}
+		}
+		fmt.Fprintln(writer, strings.Join(values, "\t"))
+	}
+	writer.Flush()
+}
+
+// watcher prints the rows of a table as they change, aligned with the columns of the initially printed table
+type watcher struct {
+	p       *printer
+	columns []column
+	widths  []int
+	rows    map[string]string
+}
+
+// watch prints the given object and returns a watcher for printing subsequent changes to the rows of its table
+func (p *printer) watch(obj interface{}, t *table) *watcher {
+	w := &watcher{
+		p:       p,
+		columns: t.columns,
+		rows:    make(map[string]string),
+	}
+	headers := w.visible(w.names())
+	w.widths = make([]int, len(headers))
+	for i, header := range headers {
+		w.widths[i] = len(header)
+	}
+	rows := make([][]string, len(t.rows))
+	for i, row := range t.rows {
+		rows[i] = w.visible(row)
+		for j, value := range rows[i] {
+			if j < len(w.widths) && len(value) > w.widths[j] {
+				w.widths[j] = len(value)
+			}
+		}
+	}
+
+	// Record the printed rows to avoid printing unchanged rows when the watch starts
+	for _, row := range rows {
+		w.rows[row[0]] = w.format(row)
+	}
+
+	if !p.isTable() {
+		p.print(obj, nil)
+		return w
+	}
+	if !p.noHeaders {
+		fmt.Fprintln(p.out, w.format(headers))
+	}
+	for _, row := range rows {
+		fmt.Fprintln(p.out, w.rows[row[0]])
+	}
+	return w
+}
+
+// update prints the given object if its row in the table has changed
+func (w *watcher) update(obj interface{}, values ...interface{}) {
+	row := make([]string, len(values))
+	for i, value := range values {
+		row[i] = fmt.Sprint(value)
+	}
+	row = w.visible(row)
+	line := w.format(row)
+	if w.rows[row[0]] == line {
+		return
+	}
+	w.rows[row[0]] = line
+
+	if w.p.isTable() {
+		fmt.Fprintln(w.p.out, line)
+	} else {
+		w.p.print(obj, nil)
+	}
+}
+
+// names returns the names of the table's columns
+func (w *watcher) names() []string {
+	names := make([]string, len(w.columns))
+	for i, column := range w.columns {
+		names[i] = column.name
+	}
+	return names
+}
+
+// visible returns the values of the columns included in the printer's output format
+func (w *watcher) visible(values []string) []string {
+	visible := make([]string, 0, len(values))
+	for i, value := range values {
+		if i < len(w.columns) && (!w.columns[i].wide || w.p.format == wideOutput) {
+			visible = append(visible, value)
+		}
+	}
+	return visible
+}
+
+// format formats the given row values aligned to the widths of the columns
+func (w *watcher) format(values []string) string {
+	cells := make([]string, len(values))
+	for i, value := range values {
+		if i < len(values)-1 && i < len(w.widths) {
+			cells[i] = fmt.Sprintf("%-*s", w.widths[i]+3, value)
+		} else {
+			cells[i] = value
+		}
+	}
+	return strings.Join(cells, "")
+}
@@ -0,0 +1,124 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+type testObject struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+}
+
+// newTestPrinter returns a printer writing to a buffer for the given output flags
+func newTestPrinter(t *testing.T, args ...string) (*printer, *bytes.Buffer) {
+	cmd := &cobra.Command{}
+	addOutputFlags(cmd)
+	assert.NoError(t, cmd.ParseFlags(args))
+	p := newPrinter(cmd)
+	buf := &bytes.Buffer{}
+	p.out = buf
+	return p, buf
+}
+
+func newTestTable() *table {
+	t := newTable(column{name: "ID"}, column{name: "STATUS"}, column{name: "NODE", wide: true})
+	t.addRow("onos-config-1", "RUNNING", "node-1")
+	t.addRow("onos-topo-1", "PENDING", "node-2")
+	return t
+}
+
+func TestPrinterFormats(t *testing.T) {
+	objs := []testObject{
+		{ID: "onos-config-1", Status: "RUNNING"},
+		{ID: "onos-topo-1", Status: "PENDING"},
+	}
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{
+			name: "table",
+			expected: "ID              STATUS\n" +
+				"onos-config-1   RUNNING\n" +
+				"onos-topo-1     PENDING\n",
+		},
+		{
+			name: "wide",
+			args: []string{"-o", "wide"},
+			expected: "ID              STATUS    NODE\n" +
+				"onos-config-1   RUNNING   node-1\n" +
+				"onos-topo-1     PENDING   node-2\n",
+		},
+		{
+			name: "no headers",
+			args: []string{"--no-headers"},
+			expected: "onos-config-1   RUNNING\n" +
+				"onos-topo-1     PENDING\n",
+		},
+		{
+			name: "json",
+			args: []string{"-o", "json"},
+			expected: "[\n" +
+				"  {\n    \"id\": \"onos-config-1\",\n    \"status\": \"RUNNING\"\n  },\n" +
+				"  {\n    \"id\": \"onos-topo-1\",\n    \"status\": \"PENDING\"\n  }\n" +
+				"]\n",
+		},
+		{
+			name: "yaml",
+			args: []string{"-o", "yaml"},
+			expected: "- id: onos-config-1\n  status: RUNNING\n" +
+				"- id: onos-topo-1\n  status: PENDING\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p, buf := newTestPrinter(t, test.args...)
+			p.print(objs, newTestTable())
+			assert.Equal(t, test.expected, buf.String())
+		})
+	}
+}
+
+func TestPrinterJSONPath(t *testing.T) {
+	obj := testObject{ID: "onos-config-1", Status: "RUNNING"}
+
+	p, buf := newTestPrinter(t, "-o", "jsonpath={.id} {.status}")
+	p.print(obj, newTestTable())
+	assert.Equal(t, "onos-config-1 RUNNING\n", buf.String())
+
+	// Templates without braces are wrapped in braces
+	p, buf = newTestPrinter(t, "-o", "jsonpath=.status")
+	p.print(obj, newTestTable())
+	assert.Equal(t, "RUNNING\n", buf.String())
+}
+
+func TestPrinterNames(t *testing.T) {
+	p, buf := newTestPrinter(t)
+	p.printNames([]string{"cluster-1", "cluster-2"})
+	assert.Equal(t, "cluster-1\ncluster-2\n", buf.String())
+
+	p, buf = newTestPrinter(t, "-o", "json")
+	p.printNames([]string{"cluster-1", "cluster-2"})
+	assert.Equal(t, "[\n  \"cluster-1\",\n  \"cluster-2\"\n]\n", buf.String())
+}
@@ -26,6 +26,9 @@ var (
 		# Remove a simulator with a given name
 		onit remove simulator <simulator-name>
 
+		# Remove a fleet of simulators added with a given prefix
+		onit remove simulators --prefix sim
+
 		# Remove a network with a given name
 		onit remove network <network-name>
 	
@@ -36,11 +39,12 @@ var (
 // getRemoveCommand returns a cobra "remove" command for removing resources from the cluster
 func getRemoveCommand() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:     "remove {simulator} [args]",
+		Use:     "remove {simulator,simulators,network,app} [args]",
 		Short:   "Remove resources from the cluster",
 		Example: removeExample,
 	}
 	cmd.AddCommand(getRemoveSimulatorCommand())
+	cmd.AddCommand(getRemoveSimulatorsCommand())
 	cmd.AddCommand(getRemoveNetworkCommand())
 	cmd.AddCommand(getRemoveAppCommand())
 	return cmd
@@ -145,6 +149,48 @@ func getRemoveSimulatorCommand() *cobra.Command {
 	return cmd
 }
 
+// getRemoveSimulatorsCommand returns a cobra command for tearing down a fleet of device simulators
+func getRemoveSimulatorsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "simulators",
+		Short: "Remove a fleet of device simulators from the cluster",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			prefix, _ := cmd.Flags().GetString("prefix")
+
+			// Get the onit controller
+			controller, err := onit.NewController()
+			if err != nil {
+				exitError(err)
+			}
+
+			// Get the cluster ID
+			clusterID, err := cmd.Flags().GetString("cluster")
+			if err != nil {
+				exitError(err)
+			}
+
+			// Get the cluster controller
+			cluster, err := controller.GetCluster(clusterID)
+			if err != nil {
+				exitError(err)
+			}
+
+			// Remove the simulators from the cluster
+			if status := cluster.RemoveSimulators(prefix); status.Failed() {
+				exitStatus(status)
+			}
+		},
+	}
+
+	cmd.Flags().StringP("cluster", "c", getDefaultCluster(), "the cluster from which to remove the simulators")
+	cmd.Flags().Lookup("cluster").Annotations = map[string][]string{
+		cobra.BashCompCustom: {"__onit_get_clusters"},
+	}
+	cmd.Flags().String("prefix", "sim", "the prefix with which the simulators were added")
+	return cmd
+}
+
 // getRemoveAppCommand returns a cobra command for tearing down an app
 func getRemoveAppCommand() *cobra.Command {
 	cmd := &cobra.Command{
@@ -0,0 +1,123 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/onosproject/onos-test/pkg/onit"
+	"github.com/spf13/cobra"
+)
+
+var (
+	rerunExample = `
+		# Rerun a test run with the same arguments
+		onit rerun <test-id>`
+
+	cancelExample = `
+		# Cancel a running test run
+		onit cancel <test-id>`
+)
+
+// getRerunCommand returns a cobra "rerun" command to rerun a test run by ID
+func getRerunCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "rerun <test-id>",
+		Short:   "Rerun a test run with the same arguments",
+		Example: rerunExample,
+		Args:    cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			testID := args[0]
+
+			// Get the onit controller
+			controller, err := onit.NewController()
+			if err != nil {
+				exitError(err)
+			}
+
+			// Get the cluster ID
+			clusterID, err := cmd.Flags().GetString("cluster")
+			if err != nil {
+				exitError(err)
+			}
+
+			// Get the cluster controller
+			cluster, err := controller.GetCluster(clusterID)
+			if err != nil {
+				exitError(err)
+			}
+
+			// Generate a new ID with the same prefix as the original test run
+			prefix := "test"
+			if strings.HasPrefix(testID, "bench") {
+				prefix = "bench"
+			}
+			newTestID := fmt.Sprintf("%s-%d", prefix, newUUIDInt())
+
+			message, code, status := cluster.RerunTests(testID, newTestID)
+			if status.Failed() {
+				exitStatus(status)
+			} else {
+				fmt.Println(message)
+				os.Exit(code)
+			}
+		},
+	}
+	cmd.Flags().StringP("cluster", "c", getDefaultCluster(), "the cluster on which to rerun the test")
+	cmd.Flags().Lookup("cluster").Annotations = map[string][]string{
+		cobra.BashCompCustom: {"__onit_get_clusters"},
+	}
+	return cmd
+}
+
+// getCancelCommand returns a cobra "cancel" command to cancel a running test run by ID
+func getCancelCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "cancel <test-id>",
+		Short:   "Cancel a running test run",
+		Example: cancelExample,
+		Args:    cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			// Get the onit controller
+			controller, err := onit.NewController()
+			if err != nil {
+				exitError(err)
+			}
+
+			// Get the cluster ID
+			clusterID, err := cmd.Flags().GetString("cluster")
+			if err != nil {
+				exitError(err)
+			}
+
+			// Get the cluster controller
+			cluster, err := controller.GetCluster(clusterID)
+			if err != nil {
+				exitError(err)
+			}
+
+			if status := cluster.CancelTests(args[0]); status.Failed() {
+				exitStatus(status)
+			}
+		},
+	}
+	cmd.Flags().StringP("cluster", "c", getDefaultCluster(), "the cluster on which to cancel the test")
+	cmd.Flags().Lookup("cluster").Annotations = map[string][]string{
+		cobra.BashCompCustom: {"__onit_get_clusters"},
+	}
+	return cmd
+}
@@ -15,8 +15,11 @@
 package cli
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"strings"
+	"text/tabwriter"
 	"time"
 
 	"github.com/onosproject/onos-test/test"
@@ -38,13 +41,16 @@ var (
 		onit run bench <name of a benchmark>
 
 		# Run a suite of benchmarks on the cluster
-		onit run bench-suite <name of a suite>`
+		onit run bench-suite <name of a suite>
+
+		# Run tests on a cluster for each combination of configurations in a matrix
+		onit run matrix -f matrix.yaml`
 )
 
 // getRunCommand returns a cobra run command to run integration tests
 func getRunCommand(registry *runner.TestRegistry) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:     "run {test,test-suite,bench,bench-suite}",
+		Use:     "run {test,test-suite,bench,bench-suite,matrix}",
 		Short:   "Run integration tests",
 		Example: runExample,
 	}
@@ -52,6 +58,7 @@ func getRunCommand(registry *runner.TestRegistry) *cobra.Command {
 	cmd.AddCommand(getRunTestSuiteCommand(registry))
 	cmd.AddCommand(getRunBenchCommand())
 	cmd.AddCommand(getRunBenchSuiteCommand(registry))
+	cmd.AddCommand(getRunMatrixCommand())
 	return cmd
 }
 
@@ -78,6 +85,7 @@ func getRunTestCommand() *cobra.Command {
 	}
 	cmd.Flags().IntP("count", "n", 0, "run tests n times")
 	cmd.Flags().IntP("timeout", "t", 60*10, "test timeout in seconds")
+	cmd.Flags().IntP("devices", "d", 0, "the number of devices to reserve for the test; all the devices in the cluster are reserved by default")
 	return cmd
 }
 
@@ -92,7 +100,15 @@ func getRunTestSuiteCommand(registry *runner.TestRegistry) *cobra.Command {
 			testSuiteNames := test.Registry.GetTestSuiteNames()
 			testSuiteName := args
 			if Subset(testSuiteName, testSuiteNames) {
-				runTestsRemote(cmd, testSuiteID, "test-suite", args, count)
+				shards, _ := cmd.Flags().GetInt("shards")
+				if shards > 1 {
+					if count > 0 {
+						exitError(errors.New("--count cannot be used with --shards"))
+					}
+					runShardedTestsRemote(cmd, registry, testSuiteID, args, shards)
+				} else {
+					runTestsRemote(cmd, testSuiteID, "test-suite", args, count)
+				}
 			} else {
 				err := fmt.Errorf("The test suite ID=%s:Name=%s does not exist", testSuiteID, testSuiteName)
 				exitError(err)
@@ -105,6 +121,8 @@ func getRunTestSuiteCommand(registry *runner.TestRegistry) *cobra.Command {
 	}
 	cmd.Flags().IntP("count", "n", 0, "run tests n times")
 	cmd.Flags().IntP("timeout", "t", 60*10, "test timeout in seconds")
+	cmd.Flags().IntP("devices", "d", 0, "the number of devices to reserve for the test; all the devices in the cluster are reserved by default")
+	cmd.Flags().Int("shards", 1, "the number of jobs across which to split the tests in the suite")
 
 	return cmd
 }
@@ -133,6 +151,7 @@ func getRunBenchCommand() *cobra.Command {
 	}
 	cmd.Flags().IntP("count", "n", 0, "the number of iterations to run")
 	cmd.Flags().IntP("timeout", "t", 60*10, "test timeout in seconds")
+	cmd.Flags().IntP("devices", "d", 0, "the number of devices to reserve for the test; all the devices in the cluster are reserved by default")
 	return cmd
 }
 
@@ -160,9 +179,86 @@ func getRunBenchSuiteCommand(registry *runner.TestRegistry) *cobra.Command {
 	}
 	cmd.Flags().IntP("count", "n", 0, "the number of iterations to run")
 	cmd.Flags().IntP("timeout", "t", 60*10, "test timeout in seconds")
+	cmd.Flags().IntP("devices", "d", 0, "the number of devices to reserve for the test; all the devices in the cluster are reserved by default")
+	return cmd
+}
+
+// getRunMatrixCommand returns a cobra command to run tests on a cluster for each combination of configurations in a matrix
+func getRunMatrixCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "matrix",
+		Short: "Run tests on a cluster for each combination of configurations in a matrix",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			file, _ := cmd.Flags().GetString("file")
+			matrix, err := onit.LoadMatrixConfig(file)
+			if err != nil {
+				exitError(err)
+			}
+
+			testSuiteNames := test.Registry.GetTestSuiteNames()
+			if !Subset(matrix.Suites, testSuiteNames) {
+				exitError(fmt.Errorf("The test suites %s do not exist", matrix.Suites))
+			}
+			testNames := test.Registry.GetTestNames()
+			if !Subset(matrix.Tests, testNames) {
+				exitError(fmt.Errorf("The tests %s do not exist", matrix.Tests))
+			}
+
+			clusters := matrix.GetClusters()
+			for _, cluster := range clusters {
+				initImageTags(cluster.Config.ImageTags)
+			}
+
+			// Get the onit controller
+			controller, err := onit.NewController()
+			if err != nil {
+				exitError(err)
+			}
+
+			matrixID := fmt.Sprintf("matrix-%d", newUUIDInt())
+			results, status := controller.RunMatrix(matrixID, matrix, clusters)
+			printMatrix(matrix, results)
+			if status.Failed() {
+				exitStatus(status)
+			}
+			for _, result := range results {
+				for _, record := range result.Records {
+					if record.Status != onit.TestPassed {
+						os.Exit(1)
+					}
+				}
+			}
+		},
+	}
+	cmd.Flags().StringP("file", "f", "", "the matrix configuration file")
+	_ = cmd.MarkFlagRequired("file")
 	return cmd
 }
 
+// printMatrix prints a grid of the results of each test run for each cluster in the matrix
+func printMatrix(matrix *onit.MatrixConfig, results []onit.MatrixResult) {
+	writer := new(tabwriter.Writer)
+	writer.Init(os.Stdout, 0, 0, 3, ' ', tabwriter.FilterHTML)
+	fmt.Fprint(writer, "CLUSTER")
+	for _, run := range matrix.GetRuns() {
+		fmt.Fprintf(writer, "\t%s", strings.ToUpper(run))
+	}
+	fmt.Fprintln(writer)
+	for _, result := range results {
+		fmt.Fprint(writer, result.Cluster.Name)
+		for i := range matrix.GetRuns() {
+			if i < len(result.Records) {
+				fmt.Fprintf(writer, "\t%s", result.Records[i].Status)
+			} else {
+				fmt.Fprint(writer, "\t-")
+			}
+		}
+		fmt.Fprintln(writer)
+	}
+	writer.Flush()
+}
+
 func runTestsRemote(cmd *cobra.Command, testID string, commandType string, tests []string, count int) {
 	// Get the onit controller
 	controller, err := onit.NewController()
@@ -187,7 +283,8 @@ func runTestsRemote(cmd *cobra.Command, testID string, commandType string, tests
 		tests = append(tests, fmt.Sprintf("-n=%d", count))
 	}
 
-	message, code, status := cluster.RunTests(testID, append([]string{commandType}, tests...), time.Duration(timeout)*time.Second)
+	devices := getDeviceCount(cmd)
+	message, code, status := cluster.RunTests(testID, append([]string{commandType}, tests...), devices, time.Duration(timeout)*time.Second)
 	if status.Failed() {
 		exitStatus(status)
 	} else {
@@ -196,3 +293,58 @@ func runTestsRemote(cmd *cobra.Command, testID string, commandType string, tests
 	}
 
 }
+
+// getDeviceCount returns the number of devices to reserve for a test run. Unless --devices is set, all the
+// devices in the cluster are reserved.
+func getDeviceCount(cmd *cobra.Command) int {
+	if !cmd.Flags().Changed("devices") {
+		return onit.AllDevices
+	}
+	devices, _ := cmd.Flags().GetInt("devices")
+	return devices
+}
+
+func runShardedTestsRemote(cmd *cobra.Command, registry *runner.TestRegistry, testID string, suites []string, shards int) {
+	if len(suites) != 1 {
+		exitError(errors.New("exactly one test suite must be specified to run sharded tests"))
+	}
+	suite := registry.TestSuites[suites[0]]
+	tests := suite.GetTestNames()
+	if len(tests) == 0 {
+		exitError(fmt.Errorf("test suite %s contains no tests", suites[0]))
+	}
+
+	// Get the onit controller
+	controller, err := onit.NewController()
+	if err != nil {
+		exitError(err)
+	}
+
+	// Get the cluster ID
+	clusterID, err := cmd.Flags().GetString("cluster")
+	if err != nil {
+		exitError(err)
+	}
+
+	// Get the cluster controller
+	cluster, err := controller.GetCluster(clusterID)
+	if err != nil {
+		exitError(err)
+	}
+
+	timeout, _ := cmd.Flags().GetInt("timeout")
+	devices := getDeviceCount(cmd)
+	record, status := cluster.RunShardedTests(testID, suites[0], tests, shards, devices, time.Duration(timeout)*time.Second)
+	if status.Failed() {
+		exitStatus(status)
+	} else {
+		passed, failed, skipped := countResults(record.Results)
+		fmt.Printf("%s %s: %d passed, %d failed, %d skipped\n", record.TestID, record.Status, passed, failed, skipped)
+		if record.Message != "" {
+			fmt.Println(record.Message)
+		}
+		if record.Status != onit.TestPassed {
+			os.Exit(1)
+		}
+	}
+}
@@ -41,9 +41,10 @@ func getSetCommand() *cobra.Command {
 // getSetClusterCommand returns a cobra command for setting the cluster context
 func getSetClusterCommand() *cobra.Command {
 	return &cobra.Command{
-		Use:   "cluster <name>",
-		Args:  cobra.ExactArgs(1),
-		Short: "Set cluster context",
+		Use:         "cluster <name>",
+		Args:        cobra.ExactArgs(1),
+		Short:       "Set cluster context",
+		Annotations: map[string]string{clusterArgAnnotation: "true"},
 		Run: func(cmd *cobra.Command, args []string) {
 			clusterID := args[0]
 
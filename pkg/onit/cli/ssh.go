@@ -15,6 +15,8 @@
 package cli
 
 import (
+	"fmt"
+
 	"github.com/onosproject/onos-test/pkg/onit"
 	"github.com/spf13/cobra"
 )
@@ -52,6 +54,8 @@ func getOnosCliCommand() *cobra.Command {
 			onosCliNodes, err := cluster.GetOnosCliNodes()
 			if err != nil {
 				exitError(err)
+			} else if len(onosCliNodes) == 0 {
+				exitError(fmt.Errorf("no onos-cli nodes found in cluster %s", clusterID))
 			}
 
 			err = cluster.OpenShell(onosCliNodes[0].ID)
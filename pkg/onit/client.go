@@ -15,32 +15,72 @@
 package onit
 
 import (
-	"errors"
+	"fmt"
+
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
-	"os"
-	"path/filepath"
 )
 
-// getRestConfig returns the Kubernetes REST API configuration
-func getRestConfig() (*rest.Config, error) {
-	kubeconfig := os.Getenv("KUBECONFIG")
-	if kubeconfig == "" {
-		home := getHomeDir()
-		if home == "" {
-			return nil, errors.New("no home directory configured")
-		}
-		kubeconfig = filepath.Join(home, ".kube", "config")
+var (
+	// kubeConfigPath is the path to the kubeconfig file to use, overriding the KUBECONFIG environment variable
+	kubeConfigPath string
+
+	// kubeContext is the kubeconfig context to use, overriding the kubeconfig's current context
+	kubeContext string
+)
+
+// SetKubeConfig sets the kubeconfig file and context used to connect to Kubernetes. If the path is empty,
+// the kubeconfig is loaded from the KUBECONFIG environment variable or ~/.kube/config. If the context is
+// empty, the kubeconfig's current context is used.
+func SetKubeConfig(path string, context string) {
+	kubeConfigPath = path
+	kubeContext = context
+}
+
+// GetKubeContext returns the name of the kubeconfig context used to connect to Kubernetes
+func GetKubeContext() (string, error) {
+	config, err := getClientConfig().RawConfig()
+	if err != nil {
+		return "", err
+	}
+	if kubeContext != "" {
+		return kubeContext, nil
 	}
+	return config.CurrentContext, nil
+}
 
-	// use the current context in kubeconfig
-	return clientcmd.BuildConfigFromFlags("", kubeconfig)
+// HasKubeContext returns whether the given context is defined in the configured kubeconfig
+func HasKubeContext(context string) (bool, error) {
+	config, err := getClientConfig().RawConfig()
+	if err != nil {
+		return false, err
+	}
+	_, ok := config.Contexts[context]
+	return ok, nil
+}
+
+// getClientConfig returns the kubeconfig loader for the configured kubeconfig file and context
+func getClientConfig() clientcmd.ClientConfig {
+	// The default loading rules merge the files listed in KUBECONFIG or fall back to ~/.kube/config
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	rules.ExplicitPath = kubeConfigPath
+	overrides := &clientcmd.ConfigOverrides{
+		CurrentContext: kubeContext,
+	}
+	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, overrides)
 }
 
-// getHomeDir returns the user's home directory if defined by environment variables
-func getHomeDir() string {
-	if h := os.Getenv("HOME"); h != "" {
-		return h
+// getRestConfig returns the Kubernetes REST API configuration
+func getRestConfig() (*rest.Config, error) {
+	clientConfig := getClientConfig()
+	if kubeContext != "" {
+		config, err := clientConfig.RawConfig()
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := config.Contexts[kubeContext]; !ok {
+			return nil, fmt.Errorf("context %s not found in kubeconfig", kubeContext)
+		}
 	}
-	return os.Getenv("USERPROFILE") // windows
+	return clientConfig.ClientConfig()
 }
@@ -25,6 +25,7 @@ import (
 
 	atomixk8s "github.com/atomix/atomix-k8s-controller/pkg/client/clientset/versioned"
 	"github.com/onosproject/onos-test/pkg/onit/console"
+	"github.com/onosproject/onos-test/pkg/onit/mininet"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	apiextension "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
@@ -72,46 +73,36 @@ func (c *ClusterController) Setup() console.ErrorStatus {
 		return c.status.Fail(err)
 	}
 	c.status.Succeed()
-	c.status.Start("Setting up Atomix controller")
-	if err := c.setupAtomixController(); err != nil {
-		return c.status.Fail(err)
-	}
-	c.status.Succeed()
-	c.status.Start("Starting Raft partitions")
-	if err := c.setupPartitions(); err != nil {
-		return c.status.Fail(err)
-	}
-	c.status.Succeed()
 	c.status.Start("Adding secrets")
 	if err := c.createOnosSecret(); err != nil {
 		return c.status.Fail(err)
 	}
-	c.status.Succeed()
-	c.status.Start("Bootstrapping onos-topo cluster")
-	if err := c.setupOnosTopo(); err != nil {
-		return c.status.Fail(err)
-	}
-	c.status.Succeed()
-	c.status.Start("Bootstrapping onos-config cluster")
-	if err := c.setupOnosConfig(); err != nil {
-		return c.status.Fail(err)
-	}
-	c.status.Succeed()
-	c.status.Start("Setting up GUI")
-	if err := c.setupGUI(); err != nil {
-		return c.status.Fail(err)
+	for _, component := range c.getComponents() {
+		c.status.Start("Setting up " + component.Description())
+		if err := c.setupComponent(component); err != nil {
+			return c.status.Fail(err)
+		}
 	}
-
 	c.status.Succeed()
-	c.status.Start("Setting up CLI")
-	if err := c.setupOnosCli(); err != nil {
-		return c.status.Fail(err)
+	if c.getExposure() != ExposeNone {
+		c.status.Start(fmt.Sprintf("Exposing services (%s)", c.getExposure()))
+		if err := c.setupExposure(); err != nil {
+			return c.status.Fail(err)
+		}
 	}
+	return c.status.Succeed()
+}
 
-	c.status.Succeed()
-	c.status.Start("Creating ingress for services")
-	if err := c.setupIngress(); err != nil {
-		return c.status.Fail(err)
+// Teardown tears down the cluster's components in the reverse of the order in which they're set up, so that
+// resources managed by a component, e.g. the Raft partitions managed by the Atomix controller, are deleted while
+// the components they depend on are still running
+func (c *ClusterController) Teardown() console.ErrorStatus {
+	components := c.getComponents()
+	for i := len(components) - 1; i >= 0; i-- {
+		c.status.Start("Tearing down " + components[i].Description())
+		if err := components[i].Teardown(c); err != nil {
+			return c.status.Fail(err)
+		}
 	}
 	return c.status.Succeed()
 }
@@ -256,7 +247,80 @@ func (c *ClusterController) AddSimulator(name string, config *SimulatorConfig) c
 		return c.status.Fail(err)
 	}
 	c.status.Start("Adding simulator to topo")
-	if err := c.addSimulatorToTopo(name); err != nil {
+	if err := c.addSimulatorToTopo(name, config); err != nil {
+		return c.status.Fail(err)
+	}
+	return c.status.Succeed()
+}
+
+// AddSimulators adds a fleet of count device simulators named <prefix>-<n> with the given configuration.
+// The simulators are created concurrently and registered in onos-topo over a single connection. If the fleet
+// cannot be added, or does not become ready within the given timeout, the simulators that were added are torn down.
+func (c *ClusterController) AddSimulators(prefix string, count int, config *SimulatorConfig, timeout time.Duration) ([]string, console.ErrorStatus) {
+	config.Fleet = prefix
+	config.setDefaults()
+	names := getFleetSimulatorNames(prefix, count)
+
+	c.status.Start(fmt.Sprintf("Setting up %d simulators", count))
+	err := forEachSimulator(names, func(index int, name string) error {
+		return c.createSimulator(name, index, config)
+	}, func(created int) {
+		c.status.Progress(fmt.Sprintf("%d/%d created", created, count))
+	})
+	if err != nil {
+		return nil, c.failFleet(err, names, nil)
+	}
+
+	c.status.Start("Waiting for simulators to become ready")
+	last := -1
+	err = c.awaitFleetReady(prefix, count, timeout, func(ready int) {
+		if ready != last {
+			c.status.Progress(fmt.Sprintf("%d/%d ready", ready, count))
+			last = ready
+		}
+	})
+	if err != nil {
+		return nil, c.failFleet(err, names, nil)
+	}
+
+	c.status.Start("Adding simulators to topo")
+	client, err := c.newTopoClient()
+	if err != nil {
+		return nil, c.failFleet(err, names, nil)
+	}
+	defer client.close()
+	for i, name := range names {
+		if err := client.addDevice(newSimulatorDevice(name, config)); err != nil {
+			return nil, c.failFleet(err, names, names[:i])
+		}
+		c.status.Progress(fmt.Sprintf("%d/%d added", i+1, count))
+	}
+	return names, c.status.Succeed()
+}
+
+// failFleet fails the current status with the given error and tears down the given simulators of a fleet that
+// could not be added, removing the given registered simulators from onos-topo
+func (c *ClusterController) failFleet(err error, names []string, registered []string) console.ErrorStatus {
+	c.status.Fail(err)
+	c.status.Start(fmt.Sprintf("Tearing down %d simulators", len(names)))
+	if len(registered) > 0 {
+		client, err := c.newTopoClient()
+		if err != nil {
+			return c.status.Fail(err)
+		}
+		defer client.close()
+		for _, name := range registered {
+			if err := client.removeDevice(name); err != nil && !IsDeviceNotFound(err) {
+				return c.status.Fail(err)
+			}
+		}
+	}
+	err = forEachSimulator(names, func(index int, name string) error {
+		return c.teardownSimulator(name)
+	}, func(deleted int) {
+		c.status.Progress(fmt.Sprintf("%d/%d deleted", deleted, len(names)))
+	})
+	if err != nil {
 		return c.status.Fail(err)
 	}
 	return c.status.Succeed()
@@ -284,17 +348,57 @@ func (c *ClusterController) AddNetwork(name string, config *NetworkConfig) conso
 	return c.status.Succeed()
 }
 
-// RunTests runs the given tests on Kubernetes
-func (c *ClusterController) RunTests(testID string, tests []string, timeout time.Duration) (string, int, console.ErrorStatus) {
+// SetNetworkLink brings the link between the given nodes of a network up or down and returns the resulting state of the link
+func (c *ClusterController) SetNetworkLink(name string, source string, target string, state mininet.LinkState) (mininet.LinkState, console.ErrorStatus) {
+	c.status.Start(fmt.Sprintf("Setting link %s-%s %s", source, target, state))
+	result, err := c.setNetworkLink(name, source, target, state)
+	if err != nil {
+		return "", c.status.Fail(err)
+	}
+	return result, c.status.Succeed()
+}
+
+// SetNetworkSwitch starts or stops the given switch of a network and returns the resulting state of the switch
+func (c *ClusterController) SetNetworkSwitch(name string, switchName string, action mininet.SwitchAction) (mininet.SwitchState, console.ErrorStatus) {
+	if action == mininet.SwitchStart {
+		c.status.Start("Starting switch " + switchName)
+	} else {
+		c.status.Start("Stopping switch " + switchName)
+	}
+	result, err := c.setNetworkSwitch(name, switchName, action)
+	if err != nil {
+		return "", c.status.Fail(err)
+	}
+	return result, c.status.Succeed()
+}
+
+// RunTests runs the given tests on Kubernetes, reserving the given number of devices for the test run.
+// If devices is AllDevices, all the devices in the cluster are reserved.
+func (c *ClusterController) RunTests(testID string, tests []string, devices int, timeout time.Duration) (string, int, console.ErrorStatus) {
 	// Default the test timeout to 10 minutes
 	if timeout == 0 {
 		timeout = 10 * time.Minute
 	}
 
+	// Cancel the test run if the user interrupts it before its logs have been streamed
+	interrupted, stopInterrupt := notifyInterrupt()
+	defer stopInterrupt()
+
+	// Reserve the devices for the test run
+	c.status.Start("Reserving devices")
+	deviceIds, err := c.acquireDevices(testID, devices, timeout, interrupted)
+	if err != nil {
+		return "", 0, c.status.Fail(err)
+	}
+	defer c.releaseDevices(testID)
+
 	// Start the test job
 	c.status.Start("Starting test job: " + testID)
-	pod, err := c.startTests(testID, tests, timeout)
-	if err != nil {
+	pod, err := c.startTests(testID, tests, deviceIds, devices, timeout, interrupted)
+	if err == errInterrupted {
+		c.CancelTests(testID)
+		return errInterrupted.Error(), 1, c.status
+	} else if err != nil {
 		return "", 0, c.status.Fail(err)
 	}
 	c.status.Succeed()
@@ -306,27 +410,101 @@ func (c *ClusterController) RunTests(testID string, tests []string, timeout time
 	}
 	defer reader.Close()
 
+	// Stop streaming the logs if the user interrupts the run
+	streamed := make(chan struct{})
+	go func() {
+		select {
+		case <-interrupted:
+			reader.Close()
+		case <-streamed:
+		}
+	}()
+
 	// Stream the logs to stdout
 	buf := make([]byte, 1024)
+	var readErr error
 	for {
 		n, err := reader.Read(buf)
 		if err != nil {
-			if err == io.EOF {
-				break
-			}
-			return "", 0, c.status
+			readErr = err
+			break
 		}
 		fmt.Print(string(buf[:n]))
 	}
+	close(streamed)
+
+	// Once all the logs have been streamed the test has finished, so later interrupts must not cancel the job
+	if readErr == io.EOF {
+		stopInterrupt()
+	} else {
+		select {
+		case <-interrupted:
+			c.CancelTests(testID)
+			return errInterrupted.Error(), 1, c.status
+		default:
+			return "", 0, c.status
+		}
+	}
 
 	// Get the exit message and code
 	message, status, err := c.getStatus(pod)
 	if err != nil {
 		return "failed to retrieve exit code", 1, c.status
 	}
+	if err := c.recordTestResults(testID, pod); err != nil {
+		c.status.Start("Recording test results")
+		c.status.Fail(err)
+	}
+
+	// If the test failed, capture the logs of the cluster's nodes before they're lost
+	if status != 0 {
+		c.status.Start("Capturing test artifacts")
+		if err := c.captureArtifacts(testID); err != nil {
+			c.status.Fail(err)
+		} else {
+			c.status.Succeed()
+		}
+	}
 	return message, status, c.status
 }
 
+// ExecuteTests runs the given tests on Kubernetes without streaming their output, returning the record of the test run.
+// Unlike RunTests, ExecuteTests does not capture artifacts when the tests fail.
+func (c *ClusterController) ExecuteTests(testID string, tests []string, devices int, timeout time.Duration) (TestRecord, error) {
+	if timeout == 0 {
+		timeout = 10 * time.Minute
+	}
+
+	deviceIds, err := c.acquireDevices(testID, devices, timeout, nil)
+	if err != nil {
+		return TestRecord{}, err
+	}
+	defer c.releaseDevices(testID)
+
+	pod, err := c.startTests(testID, tests, deviceIds, devices, timeout, nil)
+	if err != nil {
+		return TestRecord{}, err
+	}
+
+	if _, _, err := c.getStatus(pod); err != nil {
+		return TestRecord{}, err
+	}
+	if err := c.recordTestResults(testID, pod); err != nil {
+		return TestRecord{}, err
+	}
+	return c.GetRecord(testID)
+}
+
+// RerunTests starts a new test job with the arguments and timeout of the given test run
+func (c *ClusterController) RerunTests(testID string, newTestID string) (string, int, console.ErrorStatus) {
+	args, devices, timeout, err := c.getTestArgs(testID)
+	if err != nil {
+		c.status.Start("Loading test run: " + testID)
+		return "", 0, c.status.Fail(err)
+	}
+	return c.RunTests(newTestID, args, devices, timeout)
+}
+
 // GetResources returns a list of resource IDs matching the given resource name
 func (c *ClusterController) GetResources(name string) ([]string, error) {
 	pod, err := c.kubeclient.CoreV1().Pods(c.clusterID).Get(name, metav1.GetOptions{})
@@ -415,6 +593,7 @@ func (c *ClusterController) downloadLogs(pod corev1.Pod, path string, options co
 	if err != nil {
 		return err
 	}
+	defer file.Close()
 
 	// Get a stream of logs
 	req := c.kubeclient.CoreV1().Pods(c.clusterID).GetLogs(pod.Name, &options)
@@ -484,6 +663,43 @@ func (c *ClusterController) RemoveSimulator(name string) console.ErrorStatus {
 	return c.status.Succeed()
 }
 
+// RemoveSimulators removes the fleet of device simulators with the given prefix. The removal fails if no
+// simulators with the prefix exist.
+func (c *ClusterController) RemoveSimulators(prefix string) console.ErrorStatus {
+	c.status.Start(fmt.Sprintf("Finding simulators with prefix %s", prefix))
+	names, err := c.GetFleetSimulators(prefix)
+	if err != nil {
+		return c.status.Fail(err)
+	}
+	if len(names) == 0 {
+		return c.status.Fail(fmt.Errorf("no simulators with prefix %s exist", prefix))
+	}
+
+	c.status.Start(fmt.Sprintf("Tearing down %d simulators", len(names)))
+	err = forEachSimulator(names, func(index int, name string) error {
+		return c.teardownSimulator(name)
+	}, func(deleted int) {
+		c.status.Progress(fmt.Sprintf("%d/%d deleted", deleted, len(names)))
+	})
+	if err != nil {
+		return c.status.Fail(err)
+	}
+
+	c.status.Start("Reconfiguring topology")
+	client, err := c.newTopoClient()
+	if err != nil {
+		return c.status.Fail(err)
+	}
+	defer client.close()
+	for i, name := range names {
+		if err := client.removeDevice(name); err != nil && !IsDeviceNotFound(err) {
+			return c.status.Fail(err)
+		}
+		c.status.Progress(fmt.Sprintf("%d/%d removed", i+1, len(names)))
+	}
+	return c.status.Succeed()
+}
+
 // RemoveNetwork removes a stratum network with the given name
 func (c *ClusterController) RemoveNetwork(name string) console.ErrorStatus {
 	c.status.Start("Tearing down network")
@@ -16,10 +16,6 @@ package onit
 
 import (
 	"bytes"
-	"io/ioutil"
-	"k8s.io/apimachinery/pkg/labels"
-	"os"
-	"path/filepath"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -36,6 +32,9 @@ const (
 
 	// NodeFailed node has failed
 	NodeFailed NodeStatus = "FAILED"
+
+	// NodePending node is pending
+	NodePending NodeStatus = "PENDING"
 )
 
 // NodeType node type
@@ -54,6 +53,12 @@ const (
 	//OnosCli type of node is cli
 	OnosCli NodeType = "cli"
 
+	// OnosGui type of node is gui
+	OnosGui NodeType = "gui"
+
+	// Simulator type of node is simulator
+	Simulator NodeType = "simulator"
+
 	// OnosAll type of node is all
 	OnosAll NodeType = "all"
 )
@@ -72,37 +77,86 @@ const (
 
 // NodeInfo contains information about a node
 type NodeInfo struct {
-	ID     string
-	Status NodeStatus
-	Type   NodeType
+	ID       string
+	Status   NodeStatus
+	Type     NodeType
+	Ready    bool
+	Restarts int
 }
 
-// GetNodes returns a list of all onos nodes  running in the cluster
-func (c *ClusterController) GetNodes() ([]NodeInfo, error) {
-
-	onosTopoNodes, _ := c.GetOnosTopoNodes()
-	onosConfigNodes, _ := c.GetOnosConfigNodes()
-	onosCliNodes, _ := c.GetOnosCliNodes()
-	nodes := append(onosTopoNodes, onosConfigNodes...)
-	nodes = append(nodes, onosCliNodes...)
+// newNodeInfo returns the node info for the given pod
+func newNodeInfo(pod corev1.Pod, nodeType NodeType) NodeInfo {
+	node := NodeInfo{
+		ID:   pod.Name,
+		Type: nodeType,
+	}
+	switch pod.Status.Phase {
+	case corev1.PodRunning:
+		node.Status = NodeRunning
+	case corev1.PodFailed:
+		node.Status = NodeFailed
+	case corev1.PodPending:
+		node.Status = NodePending
+	}
+	if len(pod.Status.ContainerStatuses) > 0 {
+		node.Ready = pod.Status.ContainerStatuses[0].Ready
+		node.Restarts = int(pod.Status.ContainerStatuses[0].RestartCount)
+	}
+	return node
+}
 
+// GetNodes returns a list of the nodes of all the components enabled in the cluster
+func (c *ClusterController) GetNodes() ([]NodeInfo, error) {
+	nodes := make([]NodeInfo, 0)
+	for _, component := range c.getComponents() {
+		componentNodes, err := component.Nodes(c)
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, componentNodes...)
+	}
 	return nodes, nil
 }
 
-// executeCLI executes the given ONOS CLI command inside the cluster
-func (c *ClusterController) executeCLI(command string) error {
-	labelSelector := metav1.LabelSelector{MatchLabels: map[string]string{"app": "onos", "type": "cli"}}
+// getNodesOfType returns a list of the onos nodes of the given type running in the cluster
+func (c *ClusterController) getNodesOfType(nodeType NodeType) ([]NodeInfo, error) {
 	pods, err := c.kubeclient.CoreV1().Pods(c.clusterID).List(metav1.ListOptions{
-		LabelSelector: labels.Set(labelSelector.MatchLabels).String(),
+		LabelSelector: "app=onos,type=" + string(nodeType),
 	})
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	nodes := make([]NodeInfo, len(pods.Items))
+	for i, pod := range pods.Items {
+		nodes[i] = newNodeInfo(pod, nodeType)
 	}
-	return c.execute(pods.Items[0], []string{"/bin/bash", "-c", command})
+	return nodes, nil
+}
+
+// KillNode kills the given node by deleting its pod. The node is restarted by its controller.
+func (c *ClusterController) KillNode(nodeID string) error {
+	return c.kubeclient.CoreV1().Pods(c.clusterID).Delete(nodeID, &metav1.DeleteOptions{})
 }
 
 // execute executes a command in the given pod
 func (c *ClusterController) execute(pod corev1.Pod, command []string) error {
+	stdout, stderr, err := c.executeStreams(pod, command)
+	if err != nil {
+		print(string(stdout))
+		print(string(stderr))
+	}
+	return err
+}
+
+// executeOutput executes a command in the given pod and returns its standard output
+func (c *ClusterController) executeOutput(pod corev1.Pod, command []string) ([]byte, error) {
+	stdout, _, err := c.executeStreams(pod, command)
+	return stdout, err
+}
+
+// executeStreams executes a command in the given pod and returns its standard output and error
+func (c *ClusterController) executeStreams(pod corev1.Pod, command []string) ([]byte, []byte, error) {
 	container := pod.Spec.Containers[0]
 	req := c.kubeclient.CoreV1().RESTClient().Post().
 		Resource("pods").
@@ -120,7 +174,7 @@ func (c *ClusterController) execute(pod corev1.Pod, command []string) error {
 
 	exec, err := remotecommand.NewSPDYExecutor(c.restconfig, "POST", req.URL())
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 
 	var stdout, stderr bytes.Buffer
@@ -129,46 +183,5 @@ func (c *ClusterController) execute(pod corev1.Pod, command []string) error {
 		Stderr: &stderr,
 		Tty:    false,
 	})
-	if err != nil {
-		print(stdout.String())
-		print(stderr.String())
-	}
-	return err
-
-}
-
-// createOnosSecret creates a secret for configuring TLS in onos nodes and clients
-func (c *ClusterController) createOnosSecret() error {
-	secret := &corev1.Secret{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      c.clusterID,
-			Namespace: c.clusterID,
-		},
-		StringData: map[string]string{},
-	}
-
-	err := filepath.Walk(certsPath, func(path string, info os.FileInfo, errArg error) error {
-		if info.IsDir() {
-			return nil
-		}
-
-		file, err := os.Open(path)
-		if err != nil {
-			return err
-		}
-
-		fileBytes, err := ioutil.ReadAll(file)
-		if err != nil {
-			return err
-		}
-
-		secret.StringData[info.Name()] = string(fileBytes)
-		return nil
-	})
-	if err != nil {
-		return err
-	}
-
-	_, err = c.kubeclient.CoreV1().Secrets(c.clusterID).Create(secret)
-	return err
+	return stdout.Bytes(), stderr.Bytes(), err
 }
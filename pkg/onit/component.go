@@ -0,0 +1,178 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package onit
+
+import (
+	"fmt"
+	"sync"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Component is a service deployed in test clusters, e.g. onos-config or the GUI
+type Component interface {
+	// Name returns the unique name of the component, by which it's enabled or disabled in the cluster configuration
+	Name() string
+
+	// Description returns a human readable description of the component for progress output
+	Description() string
+
+	// ImageTags returns the default tags of the component's images, keyed by image name in the cluster configuration
+	ImageTags() map[string]string
+
+	// Setup creates the component's resources in the given cluster
+	Setup(c *ClusterController) error
+
+	// AwaitReady blocks until the component is ready in the given cluster
+	AwaitReady(c *ClusterController) error
+
+	// Teardown deletes the component's resources from the given cluster
+	Teardown(c *ClusterController) error
+
+	// Nodes returns the component's nodes in the given cluster
+	Nodes(c *ClusterController) ([]NodeInfo, error)
+}
+
+var (
+	componentsMu = &sync.RWMutex{}
+
+	// components is the list of registered components in the order in which they're set up
+	components = []Component{
+		&atomixComponent{},
+		&onosTopoComponent{},
+		&onosConfigComponent{},
+		&proxyComponent{},
+		&guiComponent{},
+		&onosCliComponent{},
+	}
+
+	// imageTags is the default tags of images that are not owned by a component
+	imageTags = map[string]string{
+		"simulator": string(Latest),
+		"stratum":   string(Latest),
+		"test":      string(Latest),
+	}
+)
+
+// RegisterComponent registers a component to be set up after the components already registered
+func RegisterComponent(component Component) error {
+	componentsMu.Lock()
+	defer componentsMu.Unlock()
+	for _, registered := range components {
+		if registered.Name() == component.Name() {
+			return fmt.Errorf("component %s is already registered", component.Name())
+		}
+	}
+	components = append(components, component)
+	return nil
+}
+
+// GetComponents returns the registered components in the order in which they're set up
+func GetComponents() []Component {
+	componentsMu.RLock()
+	defer componentsMu.RUnlock()
+	registered := make([]Component, len(components))
+	copy(registered, components)
+	return registered
+}
+
+// GetComponent returns the registered component with the given name
+func GetComponent(name string) (Component, error) {
+	for _, component := range GetComponents() {
+		if component.Name() == name {
+			return component, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown component %s", name)
+}
+
+// GetDefaultImageTags returns the default image tags of all the registered components
+func GetDefaultImageTags() map[string]string {
+	tags := make(map[string]string)
+	for name, tag := range imageTags {
+		tags[name] = tag
+	}
+	for _, component := range GetComponents() {
+		for name, tag := range component.ImageTags() {
+			tags[name] = tag
+		}
+	}
+	return tags
+}
+
+// getComponents returns the components enabled in the cluster in the order in which they're set up
+func (c *ClusterController) getComponents() []Component {
+	enabled := make([]Component, 0)
+	for _, component := range GetComponents() {
+		if c.config.IsEnabled(component.Name()) {
+			enabled = append(enabled, component)
+		}
+	}
+	return enabled
+}
+
+// setupComponent sets up the given component and waits for it to become ready
+func (c *ClusterController) setupComponent(component Component) error {
+	if err := component.Setup(c); err != nil {
+		return err
+	}
+	return component.AwaitReady(c)
+}
+
+// GetComponentNodes returns the nodes of the component with the given name
+func (c *ClusterController) GetComponentNodes(name string) ([]NodeInfo, error) {
+	component, err := GetComponent(name)
+	if err != nil {
+		return nil, err
+	}
+	if !c.config.IsEnabled(name) {
+		return []NodeInfo{}, nil
+	}
+	return component.Nodes(c)
+}
+
+// deleteDeployments deletes the Deployments with the given names, ignoring Deployments that don't exist
+func (c *ClusterController) deleteDeployments(names ...string) error {
+	for _, name := range names {
+		err := c.kubeclient.AppsV1().Deployments(c.clusterID).Delete(name, &metav1.DeleteOptions{})
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteServices deletes the Services with the given names, ignoring Services that don't exist
+func (c *ClusterController) deleteServices(names ...string) error {
+	for _, name := range names {
+		err := c.kubeclient.CoreV1().Services(c.clusterID).Delete(name, &metav1.DeleteOptions{})
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteConfigMaps deletes the ConfigMaps with the given names, ignoring ConfigMaps that don't exist
+func (c *ClusterController) deleteConfigMaps(names ...string) error {
+	for _, name := range names {
+		err := c.kubeclient.CoreV1().ConfigMaps(c.clusterID).Delete(name, &metav1.DeleteOptions{})
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
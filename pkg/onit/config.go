@@ -15,32 +15,115 @@
 package onit
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
 	"io/ioutil"
 	"k8s.io/api/core/v1"
 	"os"
 	"path/filepath"
 	"runtime"
+	"text/template"
+
+	"gopkg.in/yaml.v1"
 )
 
 var (
 	_, path, _, _     = runtime.Caller(0)
-	certsPath         = filepath.Join(filepath.Dir(filepath.Dir(path)), "certs")
 	configsPath       = filepath.Join(filepath.Dir(filepath.Dir(path)), "../configs")
 	deviceConfigsPath = filepath.Join(filepath.Join(filepath.Dir(filepath.Dir(path)), "../configs"), "device")
 	storeConfigsPath  = filepath.Join(filepath.Join(filepath.Dir(filepath.Dir(path)), "../configs"), "store")
 )
 
+// ExposureType is the method by which onos services are exposed outside the Kubernetes cluster
+type ExposureType string
+
+const (
+	// ExposeNone services are only reachable within the Kubernetes cluster
+	ExposeNone ExposureType = "none"
+
+	// ExposeIngress services are exposed through Ingresses
+	ExposeIngress ExposureType = "ingress"
+
+	// ExposeNodePort services are exposed on a port of each Kubernetes node
+	ExposeNodePort ExposureType = "nodeport"
+
+	// ExposeLoadBalancer services are exposed through cloud load balancers
+	ExposeLoadBalancer ExposureType = "loadbalancer"
+)
+
 // ClusterConfig provides the configuration for the Kubernetes test cluster
 type ClusterConfig struct {
-	Registry      string            `yaml:"registry" mapstructure:"registry"`
-	Preset        string            `yaml:"preset" mapstructure:"preset"`
-	ImageTags     map[string]string `yaml:"image-tags" mapstructure:"image-tags"`
-	PullPolicy    v1.PullPolicy     `yaml:"pull-policy" mapstructure:"pull-policy"`
-	ConfigNodes   int               `yaml:"configNodes" mapstructure:"topoNodes"`
-	TopoNodes     int               `yaml:"topoNodes" mapstructure:"topoNodes"`
-	Partitions    int               `yaml:"partitions" mapstructure:"partitions"`
-	PartitionSize int               `yaml:"partitionSize" mapstructure:"partitionSize"`
+	Registry      string            `yaml:"registry" json:"registry" mapstructure:"registry"`
+	Preset        string            `yaml:"preset" json:"preset" mapstructure:"preset"`
+	ImageTags     map[string]string `yaml:"imageTags" json:"imageTags" mapstructure:"imageTags"`
+	PullPolicy    v1.PullPolicy     `yaml:"pullPolicy" json:"pullPolicy" mapstructure:"pullPolicy"`
+	ConfigNodes   int               `yaml:"configNodes" json:"configNodes" mapstructure:"configNodes"`
+	TopoNodes     int               `yaml:"topoNodes" json:"topoNodes" mapstructure:"topoNodes"`
+	Partitions    int               `yaml:"partitions" json:"partitions" mapstructure:"partitions"`
+	PartitionSize int               `yaml:"partitionSize" json:"partitionSize" mapstructure:"partitionSize"`
+	Exposure      ExposureType      `yaml:"exposure" json:"exposure" mapstructure:"exposure"`
+	Components    map[string]bool   `yaml:"components" json:"components" mapstructure:"components"`
+	ModelPlugins  []ModelPlugin     `yaml:"modelPlugins" json:"modelPlugins" mapstructure:"modelPlugins"`
+}
+
+// legacyClusterConfig holds the kebab-case keys with which clusters created by earlier versions of onit stored
+// their configuration
+type legacyClusterConfig struct {
+	ImageTags  map[string]string `yaml:"image-tags"`
+	PullPolicy v1.PullPolicy     `yaml:"pull-policy"`
+}
+
+// decodeClusterConfig decodes a cluster configuration stored in a cluster ConfigMap or test job
+func decodeClusterConfig(data []byte) (*ClusterConfig, error) {
+	config := &ClusterConfig{}
+	if err := yaml.Unmarshal(data, config); err != nil {
+		return nil, err
+	}
+
+	legacy := &legacyClusterConfig{}
+	if err := yaml.Unmarshal(data, legacy); err != nil {
+		return nil, err
+	}
+	if config.ImageTags == nil {
+		config.ImageTags = legacy.ImageTags
+	}
+	if config.PullPolicy == "" {
+		config.PullPolicy = legacy.PullPolicy
+	}
+	return config, nil
+}
+
+// ModelPlugin is a device model plugin to be loaded by onos-config. A plugin is loaded from its path in the
+// onos-config image unless an image is specified, in which case the plugin is copied from its path in the image.
+type ModelPlugin struct {
+	Type    string `yaml:"type" json:"type" mapstructure:"type"`
+	Version string `yaml:"version" json:"version" mapstructure:"version"`
+	Path    string `yaml:"path,omitempty" json:"path,omitempty" mapstructure:"path"`
+	Image   string `yaml:"image,omitempty" json:"image,omitempty" mapstructure:"image"`
+}
+
+// String returns the type:version identifier of the model plugin
+func (p ModelPlugin) String() string {
+	return fmt.Sprintf("%s:%s", p.Type, p.Version)
+}
+
+// validate returns an error if the model plugin cannot be loaded. A plugin copied from an image must specify
+// its path in the image, since the file name of a plugin in its image is not known to onit.
+func (p ModelPlugin) validate() error {
+	if p.Image != "" && p.Path == "" {
+		return fmt.Errorf("model plugin %s must specify the path of the plugin in image %s", p, p.Image)
+	}
+	return nil
+}
+
+// IsEnabled returns whether the component with the given name is enabled in the cluster. Components are
+// enabled unless explicitly disabled.
+func (c *ClusterConfig) IsEnabled(component string) bool {
+	if enabled, ok := c.Components[component]; ok {
+		return enabled
+	}
+	return true
 }
 
 // load loads the preset configuration for the cluster
@@ -66,7 +149,33 @@ func (c *ClusterConfig) load() (map[string]interface{}, error) {
 
 // SimulatorConfig provides the configuration for a device simulator
 type SimulatorConfig struct {
-	Config string `yaml:"config" mapstructure:"config"`
+	Config  string `yaml:"config" mapstructure:"config"`
+	Type    string `yaml:"type" mapstructure:"type"`
+	Version string `yaml:"version" mapstructure:"version"`
+	Image   string `yaml:"image" mapstructure:"image"`
+	Port    int    `yaml:"port" mapstructure:"port"`
+	Fleet   string `yaml:"fleet,omitempty" mapstructure:"fleet"`
+
+	// ConfigFile is the path to a simulator configuration template to use instead of the preset
+	ConfigFile string `yaml:"configFile,omitempty" mapstructure:"configFile"`
+
+	// Values are custom values available to the configuration template as .Values
+	Values map[string]string `yaml:"values,omitempty" mapstructure:"values"`
+}
+
+// SimulatorTemplateData is the data with which simulator configuration templates are rendered
+type SimulatorTemplateData struct {
+	// Name is the name of the simulator
+	Name string
+
+	// Index is the index of the simulator within its fleet, or 0 for simulators added individually
+	Index int
+
+	// ClusterID is the ID of the cluster to which the simulator is added
+	ClusterID string
+
+	// Values are the custom values provided with the simulator configuration
+	Values map[string]string
 }
 
 // AppConfig provides the configuration for an app
@@ -80,22 +189,40 @@ type NetworkConfig struct {
 	MininetOptions []string
 	NumDevices     int
 	TopoType       TopoType
+	Topology       Topology
 }
 
-// load loads the simulator configuration
-func (c *SimulatorConfig) load() (map[string]interface{}, error) {
-	file, err := os.Open(filepath.Join(deviceConfigsPath, c.Config+".json"))
+// load loads the simulator configuration from the configuration file or preset, rendering it as a template
+// with the given data
+func (c *SimulatorConfig) load(data SimulatorTemplateData) (map[string]interface{}, error) {
+	path := c.ConfigFile
+	if path == "" {
+		path = filepath.Join(deviceConfigsPath, c.Config+".json")
+	}
+
+	templateBytes, err := ioutil.ReadFile(path)
 	if err != nil {
 		return nil, err
 	}
-	defer file.Close()
 
-	jsonBytes, err := ioutil.ReadAll(file)
+	tmpl, err := template.New(filepath.Base(path)).Option("missingkey=error").Parse(string(templateBytes))
 	if err != nil {
 		return nil, err
 	}
 
+	data.Values = c.Values
+	if data.Values == nil {
+		data.Values = map[string]string{}
+	}
+
+	var jsonBytes bytes.Buffer
+	if err := tmpl.Execute(&jsonBytes, data); err != nil {
+		return nil, err
+	}
+
 	var jsonObj map[string]interface{}
-	err = json.Unmarshal(jsonBytes, &jsonObj)
-	return jsonObj, err
+	if err := json.Unmarshal(jsonBytes.Bytes(), &jsonObj); err != nil {
+		return nil, fmt.Errorf("failed to parse simulator configuration %s for %s: %v", path, data.Name, err)
+	}
+	return jsonObj, nil
 }
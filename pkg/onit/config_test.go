@@ -0,0 +1,64 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package onit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestLoadDefaultSimulatorPreset(t *testing.T) {
+	config := &SimulatorConfig{Config: "default"}
+	configObj, err := config.load(SimulatorTemplateData{
+		Name:      "sim-3",
+		Index:     3,
+		ClusterID: "onit-1",
+	})
+	assert.NoError(t, err)
+
+	system := configObj["system"].(map[string]interface{})
+	systemConfig := system["config"].(map[string]interface{})
+	assert.Equal(t, "sim-3", systemConfig["hostname"])
+	assert.Equal(t, "Simulator sim-3 (3) in cluster onit-1", systemConfig["motd-banner"])
+}
+
+func TestDecodeClusterConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{
+			name: "camelCase keys",
+			data: "preset: default\nimageTags:\n  config: debug\npullPolicy: Always\nconfigNodes: 2\n",
+		},
+		{
+			name: "legacy keys",
+			data: "preset: default\nimage-tags:\n  config: debug\npull-policy: Always\nconfigNodes: 2\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config, err := decodeClusterConfig([]byte(test.data))
+			assert.NoError(t, err)
+			assert.Equal(t, "default", config.Preset)
+			assert.Equal(t, map[string]string{"config": "debug"}, config.ImageTags)
+			assert.Equal(t, corev1.PullAlways, config.PullPolicy)
+			assert.Equal(t, 2, config.ConfigNodes)
+		})
+	}
+}
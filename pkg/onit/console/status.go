@@ -15,10 +15,14 @@
 package console
 
 import (
+	"encoding/json"
 	"fmt"
-	"github.com/fatih/color"
 	"io"
 	"os"
+	"time"
+
+	"github.com/fatih/color"
+	"golang.org/x/crypto/ssh/terminal"
 )
 
 var (
@@ -26,6 +30,47 @@ var (
 	failure = color.RedString("✗")
 )
 
+// ProgressMode is the format in which the progress of long-running operations is output
+type ProgressMode string
+
+const (
+	// ProgressAuto outputs progress with a spinner when writing to a terminal and in plain text otherwise
+	ProgressAuto ProgressMode = "auto"
+
+	// ProgressTTY outputs progress with an animated spinner
+	ProgressTTY ProgressMode = "tty"
+
+	// ProgressPlain outputs timestamped lines at the start and end of each status
+	ProgressPlain ProgressMode = "plain"
+
+	// ProgressJSON outputs a JSON event for each status transition
+	ProgressJSON ProgressMode = "json"
+)
+
+// progressMode is the progress mode used by new status writers
+var progressMode = ProgressAuto
+
+// SetProgressMode sets the format in which the progress of long-running operations is output
+func SetProgressMode(mode ProgressMode) error {
+	switch mode {
+	case ProgressAuto, ProgressTTY, ProgressPlain, ProgressJSON:
+		progressMode = mode
+		return nil
+	default:
+		return fmt.Errorf("unknown progress mode %s; must be one of %s, %s, %s or %s", mode, ProgressAuto, ProgressTTY, ProgressPlain, ProgressJSON)
+	}
+}
+
+// StatusEvent is a status transition output in the JSON progress mode
+type StatusEvent struct {
+	Time     time.Time `json:"time"`
+	Event    string    `json:"event"`
+	Status   string    `json:"status"`
+	Elapsed  float64   `json:"elapsed"`
+	Progress string    `json:"progress,omitempty"`
+	Error    string    `json:"error,omitempty"`
+}
+
 // ErrorStatus tracks the errors that occurred for a long-running operation
 type ErrorStatus interface {
 	// Failed returns a boolean indicating whether any failures have occurred
@@ -38,8 +83,10 @@ type ErrorStatus interface {
 // StatusWriter provides real-time status output during onit setup operations
 type StatusWriter struct {
 	ErrorStatus
+	mode    ProgressMode
 	spinner *Spinner
 	status  string
+	started time.Time
 	writer  io.Writer
 	errors  []error
 }
@@ -47,11 +94,22 @@ type StatusWriter struct {
 // NewStatusWriter creates a new default StatusWriter
 func NewStatusWriter() *StatusWriter {
 	writer := os.Stdout
-	spinner := newSpinner(writer)
+	mode := progressMode
+	if mode == ProgressAuto {
+		if terminal.IsTerminal(int(writer.Fd())) {
+			mode = ProgressTTY
+		} else {
+			mode = ProgressPlain
+		}
+	}
+
 	s := &StatusWriter{
-		spinner: spinner,
-		writer:  writer,
-		errors:  []error{},
+		mode:   mode,
+		writer: writer,
+		errors: []error{},
+	}
+	if mode == ProgressTTY {
+		s.spinner = newSpinner(writer)
 	}
 	return s
 }
@@ -61,8 +119,40 @@ func (s *StatusWriter) Start(status string) {
 	s.Succeed()
 	// set new status
 	s.status = status
-	s.spinner.SetMessage(fmt.Sprintf(" %s ", s.status))
-	s.spinner.Spin()
+	s.started = time.Now()
+	switch s.mode {
+	case ProgressTTY:
+		s.spinner.SetMessage(fmt.Sprintf(" %s ", s.status))
+		s.spinner.Spin()
+	case ProgressPlain:
+		fmt.Fprintf(s.writer, "%s START   %s\n", s.started.Format(time.RFC3339), s.status)
+	case ProgressJSON:
+		s.writeEvent("start", nil)
+	}
+}
+
+// Progress reports the progress of the current status
+func (s *StatusWriter) Progress(progress string) {
+	if s.status == "" {
+		return
+	}
+
+	switch s.mode {
+	case ProgressTTY:
+		s.spinner.SetMessage(fmt.Sprintf(" %s (%s) ", s.status, progress))
+	case ProgressPlain:
+		fmt.Fprintf(s.writer, "%s PROGRESS %s: %s\n", time.Now().Format(time.RFC3339), s.status, progress)
+	case ProgressJSON:
+		e := StatusEvent{
+			Time:     time.Now(),
+			Event:    "progress",
+			Status:   s.status,
+			Elapsed:  s.elapsed().Seconds(),
+			Progress: progress,
+		}
+		bytes, _ := json.Marshal(e)
+		fmt.Fprintln(s.writer, string(bytes))
+	}
 }
 
 // Succeed completes the current status successfully
@@ -71,9 +161,16 @@ func (s *StatusWriter) Succeed() *StatusWriter {
 		return s
 	}
 
-	s.spinner.Stop()
-	fmt.Fprint(s.writer, "\r")
-	fmt.Fprintf(s.writer, " %s %s\n", success, s.status)
+	switch s.mode {
+	case ProgressTTY:
+		s.spinner.Stop()
+		fmt.Fprint(s.writer, "\r")
+		fmt.Fprintf(s.writer, " %s %s\n", success, s.status)
+	case ProgressPlain:
+		fmt.Fprintf(s.writer, "%s SUCCESS %s (%s)\n", time.Now().Format(time.RFC3339), s.status, s.elapsed())
+	case ProgressJSON:
+		s.writeEvent("success", nil)
+	}
 
 	s.status = ""
 	return s
@@ -85,15 +182,42 @@ func (s *StatusWriter) Fail(err error) *StatusWriter {
 		return s
 	}
 
-	s.spinner.Stop()
-	fmt.Fprint(s.writer, "\r")
-	fmt.Fprintf(s.writer, " %s %-40s %s\n", failure, s.status, err)
+	switch s.mode {
+	case ProgressTTY:
+		s.spinner.Stop()
+		fmt.Fprint(s.writer, "\r")
+		fmt.Fprintf(s.writer, " %s %-40s %s\n", failure, s.status, err)
+	case ProgressPlain:
+		fmt.Fprintf(s.writer, "%s FAILURE %s (%s): %s\n", time.Now().Format(time.RFC3339), s.status, s.elapsed(), err)
+	case ProgressJSON:
+		s.writeEvent("failure", err)
+	}
 
 	s.status = ""
 	s.errors = append(s.errors, err)
 	return s
 }
 
+// elapsed returns the time elapsed since the current status was started
+func (s *StatusWriter) elapsed() time.Duration {
+	return time.Since(s.started).Round(time.Millisecond)
+}
+
+// writeEvent writes a JSON event for a transition of the current status
+func (s *StatusWriter) writeEvent(event string, err error) {
+	e := StatusEvent{
+		Time:    time.Now(),
+		Event:   event,
+		Status:  s.status,
+		Elapsed: s.elapsed().Seconds(),
+	}
+	if err != nil {
+		e.Error = err.Error()
+	}
+	bytes, _ := json.Marshal(e)
+	fmt.Fprintln(s.writer, string(bytes))
+}
+
 // Failed returns a boolean indicating whether errors occurred
 func (s *StatusWriter) Failed() bool {
 	return len(s.errors) > 0
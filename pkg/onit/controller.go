@@ -83,8 +83,8 @@ func (c *Controller) GetClusters() (map[string]*ClusterConfig, error) {
 				return nil, err
 			}
 
-			config := &ClusterConfig{}
-			if err = yaml.Unmarshal(cm.BinaryData["config"], config); err != nil {
+			config, err := decodeClusterConfig(cm.BinaryData["config"])
+			if err != nil {
 				return nil, err
 			}
 			clusters[name] = config
@@ -151,8 +151,8 @@ func (c *Controller) GetCluster(clusterID string) (*ClusterController, error) {
 		return nil, err
 	}
 
-	config := &ClusterConfig{}
-	if err = yaml.Unmarshal(cm.BinaryData["config"], config); err != nil {
+	config, err := decodeClusterConfig(cm.BinaryData["config"])
+	if err != nil {
 		return nil, err
 	}
 
@@ -167,8 +167,12 @@ func (c *Controller) GetCluster(clusterID string) (*ClusterController, error) {
 	}, nil
 }
 
-// DeleteCluster deletes a cluster controller
+// DeleteCluster deletes a cluster controller. The cluster's components are torn down before the namespace is
+// deleted, but a failure to tear down a component does not prevent the namespace from being deleted.
 func (c *Controller) DeleteCluster(clusterID string) console.ErrorStatus {
+	if cluster, err := c.GetCluster(clusterID); err == nil {
+		cluster.Teardown()
+	}
 	c.status.Start("Deleting cluster namespace")
 	if err := c.kubeclient.RbacV1().ClusterRoleBindings().Delete(clusterID, &metav1.DeleteOptions{}); err != nil {
 		c.status.Fail(err)
@@ -0,0 +1,214 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package onit
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/onosproject/onos-topo/pkg/northbound/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// defaultDeviceVersion is the default software version of devices registered in onos-topo
+	defaultDeviceVersion = "1.0.0"
+
+	// defaultDeviceTimeout is the default request timeout of devices registered in onos-topo
+	defaultDeviceTimeout = 15 * time.Second
+)
+
+// DeviceOp is an operation on a device in onos-topo
+type DeviceOp string
+
+const (
+	// DeviceAdd is the operation of adding a device to onos-topo
+	DeviceAdd DeviceOp = "add"
+
+	// DeviceRemove is the operation of removing a device from onos-topo
+	DeviceRemove DeviceOp = "remove"
+)
+
+// Device is a device to be registered in onos-topo
+type Device struct {
+	// ID is the unique identifier of the device
+	ID string
+
+	// Address is the host:port at which the device can be reached
+	Address string
+
+	// Target is the gNMI target name of the device, defaulting to the device ID
+	Target string
+
+	// Type is the type of the device, e.g. Devicesim or Stratum
+	Type string
+
+	// Version is the software version of the device
+	Version string
+
+	// Role is the role of the device in the network
+	Role string
+
+	// Timeout is the timeout for requests to the device
+	Timeout time.Duration
+
+	// User is the user with which to connect to the device
+	User string
+
+	// Password is the password with which to connect to the device
+	Password string
+
+	// TLS is the TLS configuration for connecting to the device
+	TLS DeviceTLS
+}
+
+// DeviceTLS is the TLS configuration for connecting to a device
+type DeviceTLS struct {
+	// CaCert is the name of the device's CA certificate
+	CaCert string
+
+	// Cert is the name of the device's certificate
+	Cert string
+
+	// Key is the name of the device's TLS key
+	Key string
+
+	// Plain indicates whether to connect to the device over plaintext
+	Plain bool
+
+	// Insecure indicates whether to connect to the device without verifying its certificate
+	Insecure bool
+}
+
+// getVersion returns the software version of the device, defaulting to defaultDeviceVersion
+func (d *Device) getVersion() string {
+	if d.Version == "" {
+		return defaultDeviceVersion
+	}
+	return d.Version
+}
+
+// getTimeout returns the request timeout of the device, defaulting to defaultDeviceTimeout
+func (d *Device) getTimeout() time.Duration {
+	if d.Timeout == 0 {
+		return defaultDeviceTimeout
+	}
+	return d.Timeout
+}
+
+// toProto returns the onos-topo representation of the device
+// The device type and role are not part of the vendored onos-topo device API and are registered via the CLI.
+func (d *Device) toProto() *proto.Device {
+	device := &proto.Device{
+		Id:              d.ID,
+		Address:         d.Address,
+		Target:          d.Target,
+		SoftwareVersion: d.getVersion(),
+		Timeout:         int64(d.getTimeout()),
+		Tls: &proto.TlsConfig{
+			CaCert:   d.TLS.CaCert,
+			Cert:     d.TLS.Cert,
+			Key:      d.TLS.Key,
+			Plain:    d.TLS.Plain,
+			Insecure: d.TLS.Insecure,
+		},
+	}
+	if d.User != "" || d.Password != "" {
+		device.Credentials = &proto.Credentials{
+			User:     d.User,
+			Password: d.Password,
+		}
+	}
+	return device
+}
+
+// toCliCommand returns the onos CLI command that adds the device to onos-topo, including its type and role
+func (d *Device) toCliCommand() []string {
+	command := []string{"onos", "topo", "add", "device", d.ID,
+		"--address", d.Address,
+		"--version", d.getVersion(),
+		"--timeout", d.getTimeout().String(),
+	}
+	if d.Type != "" {
+		command = append(command, "--type", d.Type)
+	}
+	if d.Role != "" {
+		command = append(command, "--role", d.Role)
+	}
+	if d.Target != "" {
+		command = append(command, "--target", d.Target)
+	}
+	if d.User != "" {
+		command = append(command, "--user", d.User)
+	}
+	if d.Password != "" {
+		command = append(command, "--password", d.Password)
+	}
+	if d.TLS.CaCert != "" {
+		command = append(command, "--ca-cert", d.TLS.CaCert)
+	}
+	if d.TLS.Cert != "" {
+		command = append(command, "--cert", d.TLS.Cert)
+	}
+	if d.TLS.Key != "" {
+		command = append(command, "--key", d.TLS.Key)
+	}
+	if d.TLS.Plain {
+		command = append(command, "--plain")
+	}
+	if d.TLS.Insecure {
+		command = append(command, "--insecure")
+	}
+	return command
+}
+
+// DeviceError is an error adding a device to or removing a device from onos-topo
+type DeviceError struct {
+	// Op is the operation that failed
+	Op DeviceOp
+
+	// Device is the ID of the device on which the operation failed
+	Device string
+
+	// Err is the underlying error
+	Err error
+}
+
+func (e *DeviceError) Error() string {
+	return fmt.Sprintf("failed to %s device %s: %s", e.Op, e.Device, status.Convert(e.Err).Message())
+}
+
+// Unwrap returns the underlying error
+func (e *DeviceError) Unwrap() error {
+	return e.Err
+}
+
+// Code returns the gRPC status code of the underlying error
+func (e *DeviceError) Code() codes.Code {
+	return status.Code(e.Err)
+}
+
+// IsDeviceExists returns whether the given error indicates the device is already registered in onos-topo
+func IsDeviceExists(err error) bool {
+	deviceErr, ok := err.(*DeviceError)
+	return ok && deviceErr.Code() == codes.AlreadyExists
+}
+
+// IsDeviceNotFound returns whether the given error indicates the device is not registered in onos-topo
+func IsDeviceNotFound(err error) bool {
+	deviceErr, ok := err.(*DeviceError)
+	return ok && deviceErr.Code() == codes.NotFound
+}
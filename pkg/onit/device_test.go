@@ -0,0 +1,130 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package onit
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/onosproject/onos-topo/pkg/northbound/proto"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestDeviceToProto(t *testing.T) {
+	tests := []struct {
+		name     string
+		device   *Device
+		expected *proto.Device
+	}{
+		{
+			name:   "defaults",
+			device: &Device{ID: "sim-1", Address: "sim-1:11161", TLS: DeviceTLS{Plain: true}},
+			expected: &proto.Device{
+				Id:              "sim-1",
+				Address:         "sim-1:11161",
+				SoftwareVersion: "1.0.0",
+				Timeout:         int64(15 * time.Second),
+				Tls:             &proto.TlsConfig{Plain: true},
+			},
+		},
+		{
+			name: "all attributes",
+			device: &Device{
+				ID:       "td-1",
+				Address:  "td-1:10161",
+				Target:   "td",
+				Type:     "TestDevice",
+				Version:  "2.0.0",
+				Timeout:  5 * time.Second,
+				User:     "admin",
+				Password: "secret",
+				TLS:      DeviceTLS{CaCert: "ca.crt", Cert: "td.crt", Key: "td.key", Insecure: true},
+			},
+			expected: &proto.Device{
+				Id:              "td-1",
+				Address:         "td-1:10161",
+				Target:          "td",
+				SoftwareVersion: "2.0.0",
+				Timeout:         int64(5 * time.Second),
+				Credentials:     &proto.Credentials{User: "admin", Password: "secret"},
+				Tls:             &proto.TlsConfig{CaCert: "ca.crt", Cert: "td.crt", Key: "td.key", Insecure: true},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, test.device.toProto())
+		})
+	}
+}
+
+func TestDeviceError(t *testing.T) {
+	exists := &DeviceError{Op: DeviceAdd, Device: "sim-1", Err: status.Error(codes.AlreadyExists, "device exists")}
+	assert.Equal(t, "failed to add device sim-1: device exists", exists.Error())
+	assert.True(t, IsDeviceExists(exists))
+	assert.False(t, IsDeviceNotFound(exists))
+
+	notFound := &DeviceError{Op: DeviceRemove, Device: "sim-1", Err: status.Error(codes.NotFound, "device not found")}
+	assert.True(t, IsDeviceNotFound(notFound))
+	assert.False(t, IsDeviceExists(notFound))
+
+	assert.False(t, IsDeviceNotFound(errors.New("device not found")))
+}
+
+func TestDeviceToCliCommand(t *testing.T) {
+	tests := []struct {
+		name     string
+		device   *Device
+		expected []string
+	}{
+		{
+			name:   "defaults",
+			device: &Device{ID: "sim-1", Address: "sim-1:11161", Type: "Devicesim", TLS: DeviceTLS{Plain: true}},
+			expected: []string{"onos", "topo", "add", "device", "sim-1",
+				"--address", "sim-1:11161", "--version", "1.0.0", "--timeout", "15s",
+				"--type", "Devicesim", "--plain"},
+		},
+		{
+			name: "all attributes",
+			device: &Device{
+				ID:       "s1",
+				Address:  "net-s1:50001",
+				Target:   "s1",
+				Type:     "Stratum",
+				Role:     "leaf",
+				Version:  "2.0.0",
+				Timeout:  5 * time.Second,
+				User:     "admin",
+				Password: "secret",
+				TLS:      DeviceTLS{CaCert: "ca.crt", Cert: "s1.crt", Key: "s1.key", Insecure: true},
+			},
+			expected: []string{"onos", "topo", "add", "device", "s1",
+				"--address", "net-s1:50001", "--version", "2.0.0", "--timeout", "5s",
+				"--type", "Stratum", "--role", "leaf", "--target", "s1",
+				"--user", "admin", "--password", "secret",
+				"--ca-cert", "ca.crt", "--cert", "s1.crt", "--key", "s1.key", "--insecure"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, test.device.toCliCommand())
+		})
+	}
+}
@@ -0,0 +1,187 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package onit
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Endpoint is an address through which an onos service can be reached
+type Endpoint struct {
+	Name     string
+	Service  string
+	Exposure ExposureType
+	Address  string
+	Host     string
+}
+
+// exposedService is an onos service port exposed outside the Kubernetes cluster
+type exposedService struct {
+	endpoint string
+	service  string
+	port     string
+	ingress  string
+	host     string
+}
+
+// exposedServices is the list of onos service ports exposed outside the Kubernetes cluster
+var exposedServices = []exposedService{
+	{endpoint: "gnmi", service: "onos-config", port: "grpc", ingress: grpcIngressName},
+	{endpoint: "topo", service: "onos-topo", port: "grpc", ingress: grpcIngressName},
+	{endpoint: "gui", service: "onos-gui", port: "grpc", ingress: guiIngressName, host: guiIngressHost},
+}
+
+// pendingAddress is the address of endpoints that have not yet been assigned an external address
+const pendingAddress = "<pending>"
+
+// getExposure returns the exposure type of the cluster, defaulting to ingress for clusters created before
+// the exposure was configurable
+func (c *ClusterController) getExposure() ExposureType {
+	if c.config.Exposure == "" {
+		return ExposeIngress
+	}
+	return c.config.Exposure
+}
+
+// setupExposure exposes the onos services outside the Kubernetes cluster
+func (c *ClusterController) setupExposure() error {
+	switch c.getExposure() {
+	case ExposeNone:
+		return nil
+	case ExposeIngress:
+		return c.setupIngress()
+	case ExposeNodePort:
+		return c.exposeServices(corev1.ServiceTypeNodePort)
+	case ExposeLoadBalancer:
+		return c.exposeServices(corev1.ServiceTypeLoadBalancer)
+	default:
+		return fmt.Errorf("unknown exposure type %s", c.config.Exposure)
+	}
+}
+
+// exposeServices changes the type of the exposed onos services to the given service type
+func (c *ClusterController) exposeServices(serviceType corev1.ServiceType) error {
+	for _, exposed := range exposedServices {
+		service, err := c.kubeclient.CoreV1().Services(c.clusterID).Get(exposed.service, metav1.GetOptions{})
+		if err != nil {
+			if k8serrors.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+		if service.Spec.Type == serviceType {
+			continue
+		}
+		service.Spec.Type = serviceType
+		if _, err := c.kubeclient.CoreV1().Services(c.clusterID).Update(service); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetEndpoints returns the addresses through which the onos services can be reached
+func (c *ClusterController) GetEndpoints() ([]Endpoint, error) {
+	exposure := c.getExposure()
+	var nodeAddress string
+	if exposure == ExposeNodePort {
+		address, err := c.getNodeAddress()
+		if err != nil {
+			return nil, err
+		}
+		nodeAddress = address
+	}
+
+	endpoints := make([]Endpoint, 0, len(exposedServices))
+	for _, exposed := range exposedServices {
+		service, err := c.kubeclient.CoreV1().Services(c.clusterID).Get(exposed.service, metav1.GetOptions{})
+		if err != nil {
+			if k8serrors.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+
+		var port corev1.ServicePort
+		for _, servicePort := range service.Spec.Ports {
+			if servicePort.Name == exposed.port {
+				port = servicePort
+			}
+		}
+
+		endpoint := Endpoint{
+			Name:     exposed.endpoint,
+			Service:  exposed.service,
+			Exposure: exposure,
+		}
+		switch exposure {
+		case ExposeNone:
+			endpoint.Address = fmt.Sprintf("%s.%s.svc.cluster.local:%d", exposed.service, c.clusterID, port.Port)
+		case ExposeIngress:
+			address, err := c.getIngressAddress(exposed.ingress)
+			if err != nil && !k8serrors.IsNotFound(err) {
+				return nil, err
+			}
+			endpoint.Address = pendingAddress
+			if address != "" {
+				// The gRPC ingress terminates TLS, while the GUI is served over HTTP
+				if exposed.host == "" {
+					endpoint.Address = fmt.Sprintf("%s:443", address)
+				} else {
+					endpoint.Address = fmt.Sprintf("%s:80", address)
+				}
+			}
+			endpoint.Host = exposed.host
+		case ExposeNodePort:
+			endpoint.Address = pendingAddress
+			if nodeAddress != "" && port.NodePort != 0 {
+				endpoint.Address = fmt.Sprintf("%s:%d", nodeAddress, port.NodePort)
+			}
+		case ExposeLoadBalancer:
+			endpoint.Address = pendingAddress
+			for _, lb := range service.Status.LoadBalancer.Ingress {
+				if lb.IP != "" {
+					endpoint.Address = fmt.Sprintf("%s:%d", lb.IP, port.Port)
+				} else if lb.Hostname != "" {
+					endpoint.Address = fmt.Sprintf("%s:%d", lb.Hostname, port.Port)
+				}
+			}
+		}
+		endpoints = append(endpoints, endpoint)
+	}
+	return endpoints, nil
+}
+
+// getNodeAddress returns an address through which the Kubernetes nodes can be reached, preferring external addresses
+func (c *ClusterController) getNodeAddress() (string, error) {
+	nodes, err := c.kubeclient.CoreV1().Nodes().List(metav1.ListOptions{})
+	if err != nil {
+		return "", err
+	}
+	for _, addressType := range []corev1.NodeAddressType{corev1.NodeExternalIP, corev1.NodeInternalIP} {
+		for _, node := range nodes.Items {
+			for _, address := range node.Status.Addresses {
+				if address.Type == addressType {
+					return address.Address, nil
+				}
+			}
+		}
+	}
+	return "", nil
+}
@@ -0,0 +1,159 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package onit
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/transport/spdy"
+)
+
+// dialTimeout is the timeout for connecting to onos services
+const dialTimeout = 15 * time.Second
+
+// dialService returns a connection to the given onos service along with a channel to be closed once the
+// connection is no longer needed. When running inside the cluster the connection is made directly to the
+// service, otherwise it is made through a port forwarded to a ready node of the given type.
+func (c *ClusterController) dialService(nodeType NodeType, service string, port int) (*grpc.ClientConn, chan struct{}, error) {
+	tlsConfig, err := c.getClientCredentials(service)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	stop := make(chan struct{})
+	address := fmt.Sprintf("%s.%s.svc.cluster.local:%d", service, c.clusterID, port)
+	if !inCluster() {
+		pod, err := c.getReadyNode(nodeType)
+		if err != nil {
+			close(stop)
+			return nil, nil, err
+		}
+		localPort, err := c.forwardPort(pod, port, stop)
+		if err != nil {
+			close(stop)
+			return nil, nil, err
+		}
+		address = fmt.Sprintf("localhost:%d", localPort)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), dialTimeout)
+	defer cancel()
+	conn, err := grpc.DialContext(ctx, address, grpc.WithBlock(), grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
+	if err != nil {
+		close(stop)
+		return nil, nil, fmt.Errorf("failed to connect to %s: %v", service, err)
+	}
+	return conn, stop, nil
+}
+
+// getReadyNode returns the name of a ready pod of the given node type
+func (c *ClusterController) getReadyNode(nodeType NodeType) (string, error) {
+	nodes, err := c.getNodesOfType(nodeType)
+	if err != nil {
+		return "", err
+	}
+	for _, node := range nodes {
+		if node.Status == NodeRunning && node.Ready {
+			return node.ID, nil
+		}
+	}
+	return "", fmt.Errorf("no %s node is ready", nodeType)
+}
+
+// inCluster returns whether onit is running inside a Kubernetes cluster
+func inCluster() bool {
+	return os.Getenv("KUBERNETES_SERVICE_HOST") != ""
+}
+
+// getClientCredentials returns TLS credentials for connecting to the given onos service using the cluster's
+// client certificate
+func (c *ClusterController) getClientCredentials(service string) (*tls.Config, error) {
+	secret, err := c.kubeclient.CoreV1().Secrets(c.clusterID).Get(c.clusterID, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	certPool := x509.NewCertPool()
+	if !certPool.AppendCertsFromPEM(secret.Data[caCertKey]) {
+		return nil, errors.New("failed to append CA certificates")
+	}
+
+	cert, err := tls.X509KeyPair(secret.Data[clientCertKey], secret.Data[clientKeyKey])
+	if err != nil {
+		return nil, err
+	}
+
+	// The server certificate is verified against the service name rather than the forwarded address
+	return &tls.Config{
+		RootCAs:      certPool,
+		Certificates: []tls.Certificate{cert},
+		ServerName:   service,
+	}, nil
+}
+
+// forwardPort forwards a random local port to the given port on the given pod until stop is closed,
+// returning the local port
+func (c *ClusterController) forwardPort(podName string, remotePort int, stop chan struct{}) (int, error) {
+	req := c.kubeclient.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Name(podName).
+		Namespace(c.clusterID).
+		SubResource("portforward")
+
+	roundTripper, upgradeRoundTripper, err := spdy.RoundTripperFor(c.restconfig)
+	if err != nil {
+		return 0, err
+	}
+	dialer := spdy.NewDialer(upgradeRoundTripper, &http.Client{Transport: roundTripper}, http.MethodPost, req.URL())
+
+	ready := make(chan struct{})
+	out, errOut := new(bytes.Buffer), new(bytes.Buffer)
+	forwarder, err := portforward.New(dialer, []string{fmt.Sprintf(":%d", remotePort)}, stop, ready, out, errOut)
+	if err != nil {
+		return 0, err
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- forwarder.ForwardPorts()
+	}()
+
+	select {
+	case <-ready:
+	case err := <-errCh:
+		if err == nil {
+			err = errors.New(errOut.String())
+		}
+		return 0, err
+	}
+
+	ports, err := forwarder.GetPorts()
+	if err != nil {
+		return 0, err
+	}
+	return int(ports[0].Local), nil
+}
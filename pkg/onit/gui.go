@@ -22,18 +22,53 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-// setupGUI sets up the GUI resources
-func (c *ClusterController) setupGUI() error {
+// guiComponent is the onos-gui component
+type guiComponent struct{}
+
+// Name returns the name of the GUI component
+func (*guiComponent) Name() string {
+	return string(OnosGui)
+}
+
+// Description returns the description of the GUI component
+func (*guiComponent) Description() string {
+	return "GUI"
+}
+
+// ImageTags returns the default image tags for the GUI
+func (*guiComponent) ImageTags() map[string]string {
+	return map[string]string{
+		"gui": string(Latest),
+	}
+}
+
+// Setup sets up the GUI resources
+func (*guiComponent) Setup(c *ClusterController) error {
 	if err := c.createGUIDeployment(); err != nil {
 		return err
 	}
 	if err := c.createGUIService(); err != nil {
 		return err
 	}
-	if err := c.awaitGUIDeploymentReady(); err != nil {
+	return nil
+}
+
+// AwaitReady waits for the GUI Deployment to become ready
+func (*guiComponent) AwaitReady(c *ClusterController) error {
+	return c.awaitGUIDeploymentReady()
+}
+
+// Teardown deletes the GUI resources
+func (*guiComponent) Teardown(c *ClusterController) error {
+	if err := c.deleteDeployments("onos-gui"); err != nil {
 		return err
 	}
-	return nil
+	return c.deleteServices("onos-gui")
+}
+
+// Nodes returns the GUI nodes
+func (*guiComponent) Nodes(c *ClusterController) ([]NodeInfo, error) {
+	return c.getNodesOfType(OnosGui)
 }
 
 // createGUIDeployment creates an onos-gui deployment
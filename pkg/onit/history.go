@@ -0,0 +1,156 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package onit
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/onosproject/onos-test/pkg/onit/console"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// HistoryFilter filters the records in the test history
+type HistoryFilter struct {
+	Status TestStatus
+	Type   TestType
+	Test   string
+	Since  time.Duration
+}
+
+// Matches returns a boolean indicating whether the given record matches the filter
+func (f HistoryFilter) Matches(record TestRecord) bool {
+	if f.Status != "" && !strings.EqualFold(string(f.Status), string(record.Status)) {
+		return false
+	}
+	if f.Type != "" && f.Type != record.Type {
+		return false
+	}
+	if f.Since > 0 && record.StartTime.Before(time.Now().Add(-f.Since)) {
+		return false
+	}
+	if f.Test != "" && !record.hasTest(f.Test) {
+		return false
+	}
+	return true
+}
+
+// hasTest returns a boolean indicating whether the given test or suite was part of the run
+func (r TestRecord) hasTest(name string) bool {
+	for _, arg := range r.Args {
+		if arg == name {
+			return true
+		}
+	}
+	for _, result := range r.Results {
+		if result.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// FilterHistory returns the records from the given history that match the given filter
+func FilterHistory(records []TestRecord, filter HistoryFilter) []TestRecord {
+	filtered := make([]TestRecord, 0, len(records))
+	for _, record := range records {
+		if filter.Matches(record) {
+			filtered = append(filtered, record)
+		}
+	}
+	return filtered
+}
+
+// DeleteHistory deletes the jobs and pods of completed test runs older than the given duration
+func (c *ClusterController) DeleteHistory(olderThan time.Duration) ([]string, console.ErrorStatus) {
+	c.status.Start("Pruning test history")
+	records, err := c.GetHistory()
+	if err != nil {
+		return nil, c.status.Fail(err)
+	}
+
+	deleted := make([]string, 0)
+	for _, record := range records {
+		if record.Status == TestRunning {
+			continue
+		}
+		if olderThan > 0 && record.StartTime.After(time.Now().Add(-olderThan)) {
+			continue
+		}
+		if err := c.deleteTestJob(record.TestID); err != nil {
+			return deleted, c.status.Fail(err)
+		}
+		deleted = append(deleted, record.TestID)
+	}
+	return deleted, c.status.Succeed()
+}
+
+// deleteTestJob deletes the job and pods for the given test
+func (c *ClusterController) deleteTestJob(testID string) error {
+	propagation := metav1.DeletePropagationBackground
+	err := c.kubeclient.BatchV1().Jobs(c.clusterID).Delete(testID, &metav1.DeleteOptions{
+		PropagationPolicy: &propagation,
+	})
+	if !k8serrors.IsNotFound(err) {
+		return err
+	}
+
+	// Sharded test runs are recorded as one job per shard
+	return c.kubeclient.BatchV1().Jobs(c.clusterID).DeleteCollection(&metav1.DeleteOptions{
+		PropagationPolicy: &propagation,
+	}, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", shardLabel, testID),
+	})
+}
+
+// ExportHistory writes the records and logs of the given test runs to the given directory
+func (c *ClusterController) ExportHistory(records []TestRecord, path string) console.ErrorStatus {
+	c.status.Start("Exporting test history")
+	if err := os.MkdirAll(path, 0755); err != nil {
+		return c.status.Fail(err)
+	}
+
+	for _, record := range records {
+		if err := c.exportRecord(record, path); err != nil {
+			return c.status.Fail(err)
+		}
+	}
+	return c.status.Succeed()
+}
+
+// exportRecord writes the given record and the logs of its test pod to the given directory
+func (c *ClusterController) exportRecord(record TestRecord, path string) error {
+	bytes, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(filepath.Join(path, fmt.Sprintf("%s.json", record.TestID)), bytes, 0644); err != nil {
+		return err
+	}
+
+	// If the test pod has already been removed, only the record can be exported
+	pod, err := c.getPod(record.TestID)
+	if err != nil {
+		return nil
+	}
+	return c.downloadLogs(pod, filepath.Join(path, fmt.Sprintf("%s.log", record.TestID)), corev1.PodLogOptions{})
+}
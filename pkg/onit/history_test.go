@@ -0,0 +1,108 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package onit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilterHistory(t *testing.T) {
+	now := time.Now()
+	records := []TestRecord{
+		{
+			TestID:    "test-1",
+			Type:      TestTypeSuite,
+			Args:      []string{"integration-tests"},
+			Status:    TestPassed,
+			StartTime: now.Add(-2 * time.Hour),
+			Results:   []TestResult{{Name: "single-path", Status: TestPassed}},
+		},
+		{
+			TestID:    "test-2",
+			Type:      TestTypeTest,
+			Args:      []string{"single-path"},
+			Status:    TestFailed,
+			StartTime: now.Add(-30 * time.Minute),
+		},
+		{
+			TestID:    "test-3",
+			Type:      TestTypeSuite,
+			Args:      []string{"integration-tests"},
+			Status:    TestRunning,
+			StartTime: now.Add(-time.Minute),
+		},
+	}
+
+	tests := []struct {
+		name     string
+		filter   HistoryFilter
+		expected []string
+	}{
+		{
+			name:     "no filter",
+			filter:   HistoryFilter{},
+			expected: []string{"test-1", "test-2", "test-3"},
+		},
+		{
+			name:     "status",
+			filter:   HistoryFilter{Status: TestFailed},
+			expected: []string{"test-2"},
+		},
+		{
+			name:     "status in another case",
+			filter:   HistoryFilter{Status: TestStatus("passed")},
+			expected: []string{"test-1"},
+		},
+		{
+			name:     "type",
+			filter:   HistoryFilter{Type: TestTypeSuite},
+			expected: []string{"test-1", "test-3"},
+		},
+		{
+			name:     "test in args or results",
+			filter:   HistoryFilter{Test: "single-path"},
+			expected: []string{"test-1", "test-2"},
+		},
+		{
+			name:     "since",
+			filter:   HistoryFilter{Since: time.Hour},
+			expected: []string{"test-2", "test-3"},
+		},
+		{
+			name:     "combined",
+			filter:   HistoryFilter{Type: TestTypeSuite, Since: time.Hour},
+			expected: []string{"test-3"},
+		},
+		{
+			name:     "no matches",
+			filter:   HistoryFilter{Test: "subscribe"},
+			expected: []string{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			filtered := FilterHistory(records, test.filter)
+			ids := make([]string, len(filtered))
+			for i, record := range filtered {
+				ids[i] = record.TestID
+			}
+			assert.Equal(t, test.expected, ids)
+		})
+	}
+}
@@ -15,125 +15,210 @@
 package onit
 
 import (
-	extensionsv1beta1 "k8s.io/api/extensions/v1beta1"
+	"errors"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+const (
+	// guiIngressHost is the host through which the GUI is exposed by the ingress
+	guiIngressHost = "onos-gui"
+
+	// grpcIngressName is the name of the ingress for onos gRPC services
+	grpcIngressName = "onos-ingress"
+
+	// guiIngressName is the name of the ingress for the GUI
+	guiIngressName = "onos-gui-ingress"
 )
 
+// ingressVersions is the list of API versions in which Ingresses can be created, in order of preference
+var ingressVersions = []schema.GroupVersion{
+	{Group: "networking.k8s.io", Version: "v1"},
+	{Group: "networking.k8s.io", Version: "v1beta1"},
+	{Group: "extensions", Version: "v1beta1"},
+}
+
+// ingressPath is a path routed by an ingress to a service port
+type ingressPath struct {
+	path    string
+	service string
+	port    interface{}
+}
+
 // setupIngress sets up the Ingress
 func (c *ClusterController) setupIngress() error {
-	if err := c.createGRPCIngress(); err != nil {
+	version, err := c.getIngressVersion()
+	if err != nil {
 		return err
 	}
-	if err := c.createGUIIngress(); err != nil {
-		return err
+	if c.config.IsEnabled(string(OnosConfig)) || c.config.IsEnabled(string(OnosTopo)) {
+		if err := c.createGRPCIngress(version); err != nil {
+			return err
+		}
+	}
+	if c.config.IsEnabled(string(OnosGui)) {
+		if err := c.createGUIIngress(version); err != nil {
+			return err
+		}
 	}
 	return nil
 }
 
+// getIngressVersion returns the preferred Ingress API version supported by the server
+func (c *ClusterController) getIngressVersion() (schema.GroupVersion, error) {
+	for _, version := range ingressVersions {
+		resources, err := c.kubeclient.Discovery().ServerResourcesForGroupVersion(version.String())
+		if err != nil {
+			if k8serrors.IsNotFound(err) {
+				continue
+			}
+			return schema.GroupVersion{}, err
+		}
+		for _, resource := range resources.APIResources {
+			if resource.Name == "ingresses" {
+				return version, nil
+			}
+		}
+	}
+	return schema.GroupVersion{}, errors.New("the server does not support Ingresses")
+}
+
 // createGRPCIngress creates an ingress for onos services
-func (c *ClusterController) createGRPCIngress() error {
-	ing := &extensionsv1beta1.Ingress{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      "onos-ingress",
-			Namespace: c.clusterID,
-			Annotations: map[string]string{
-				"kubernetes.io/ingress.class": "nginx",
-				// gRPC services that can be routed by path
-				"nginx.org/grpc-services": "onos-config,onos-topo",
-				// Insecure backend gRPC protocol
-				"nginx.ingress.kubernetes.io/backend-protocol": "GRPC",
-			},
-		},
-		Spec: extensionsv1beta1.IngressSpec{
-			TLS: []extensionsv1beta1.IngressTLS{
-				{
-					SecretName: c.clusterID,
-				},
-			},
-			Rules: []extensionsv1beta1.IngressRule{
-				{
-					IngressRuleValue: extensionsv1beta1.IngressRuleValue{
-						HTTP: &extensionsv1beta1.HTTPIngressRuleValue{
-							Paths: []extensionsv1beta1.HTTPIngressPath{
-								{
-									Path: "/gnmi.gNMI",
-									Backend: extensionsv1beta1.IngressBackend{
-										ServiceName: "onos-config",
-										ServicePort: intstr.FromString("grpc"),
-									},
-								},
-							},
-						},
-					},
-				},
-				{
-					IngressRuleValue: extensionsv1beta1.IngressRuleValue{
-						HTTP: &extensionsv1beta1.HTTPIngressRuleValue{
-							Paths: []extensionsv1beta1.HTTPIngressPath{
-								{
-									Path: "/proto.DeviceInventoryService",
-									Backend: extensionsv1beta1.IngressBackend{
-										ServiceName: "onos-config",
-										ServicePort: intstr.FromString("grpc"),
-									},
-								},
-							},
-						},
-					},
-				},
-				{
-					IngressRuleValue: extensionsv1beta1.IngressRuleValue{
-						HTTP: &extensionsv1beta1.HTTPIngressRuleValue{
-							Paths: []extensionsv1beta1.HTTPIngressPath{
-								{
-									Path: "/proto.DeviceService",
-									Backend: extensionsv1beta1.IngressBackend{
-										ServiceName: "onos-topo",
-										ServicePort: intstr.FromString("grpc"),
-									},
-								},
-							},
-						},
-					},
-				},
+func (c *ClusterController) createGRPCIngress(version schema.GroupVersion) error {
+	annotations := map[string]interface{}{
+		"kubernetes.io/ingress.class": "nginx",
+		// gRPC services that can be routed by path
+		"nginx.org/grpc-services": "onos-config,onos-topo",
+		// Insecure backend gRPC protocol
+		"nginx.ingress.kubernetes.io/backend-protocol": "GRPC",
+	}
+	rules := []interface{}{}
+	if c.config.IsEnabled(string(OnosConfig)) {
+		rules = append(rules,
+			newIngressRule(version, "", ingressPath{path: "/gnmi.gNMI", service: "onos-config", port: "grpc"}),
+			newIngressRule(version, "", ingressPath{path: "/proto.DeviceInventoryService", service: "onos-config", port: "grpc"}))
+	}
+	if c.config.IsEnabled(string(OnosTopo)) {
+		rules = append(rules,
+			newIngressRule(version, "", ingressPath{path: "/proto.DeviceService", service: "onos-topo", port: "grpc"}))
+	}
+	spec := map[string]interface{}{
+		"tls": []interface{}{
+			map[string]interface{}{
+				"secretName": c.clusterID,
 			},
 		},
+		"rules": rules,
 	}
-	_, err := c.kubeclient.ExtensionsV1beta1().Ingresses(c.clusterID).Create(ing)
-	return err
+	return c.createIngress(version, grpcIngressName, annotations, spec)
 }
 
 // createGUIIngress creates an ingress for the GUI
-func (c *ClusterController) createGUIIngress() error {
-	ing := &extensionsv1beta1.Ingress{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      "onos-gui-ingress",
-			Namespace: c.clusterID,
-			Annotations: map[string]string{
-				"kubernetes.io/ingress.class": "nginx",
+func (c *ClusterController) createGUIIngress(version schema.GroupVersion) error {
+	annotations := map[string]interface{}{
+		"kubernetes.io/ingress.class": "nginx",
+	}
+	spec := map[string]interface{}{
+		"rules": []interface{}{
+			newIngressRule(version, guiIngressHost, ingressPath{path: "/", service: "onos-gui", port: int64(80)}),
+		},
+	}
+	return c.createIngress(version, guiIngressName, annotations, spec)
+}
+
+// createIngress creates an Ingress with the given name, annotations and spec in the given API version
+func (c *ClusterController) createIngress(version schema.GroupVersion, name string, annotations map[string]interface{}, spec map[string]interface{}) error {
+	client, err := dynamic.NewForConfig(c.restconfig)
+	if err != nil {
+		return err
+	}
+	ing := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": version.String(),
+			"kind":       "Ingress",
+			"metadata": map[string]interface{}{
+				"name":        name,
+				"namespace":   c.clusterID,
+				"annotations": annotations,
 			},
+			"spec": spec,
 		},
-		Spec: extensionsv1beta1.IngressSpec{
-			Rules: []extensionsv1beta1.IngressRule{
-				{
-					Host: "onos-gui",
-					IngressRuleValue: extensionsv1beta1.IngressRuleValue{
-						HTTP: &extensionsv1beta1.HTTPIngressRuleValue{
-							Paths: []extensionsv1beta1.HTTPIngressPath{
-								{
-									Backend: extensionsv1beta1.IngressBackend{
-										ServiceName: "onos-gui",
-										ServicePort: intstr.FromInt(80),
-									},
-								},
-							},
-						},
-					},
+	}
+	_, err = client.Resource(version.WithResource("ingresses")).Namespace(c.clusterID).Create(ing, metav1.CreateOptions{})
+	return err
+}
+
+// getIngressAddress returns the address of the load balancer for the given ingress, or an empty string if
+// no address has been assigned
+func (c *ClusterController) getIngressAddress(name string) (string, error) {
+	version, err := c.getIngressVersion()
+	if err != nil {
+		return "", err
+	}
+	client, err := dynamic.NewForConfig(c.restconfig)
+	if err != nil {
+		return "", err
+	}
+	ing, err := client.Resource(version.WithResource("ingresses")).Namespace(c.clusterID).Get(name, metav1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+	ingresses, _, err := unstructured.NestedSlice(ing.Object, "status", "loadBalancer", "ingress")
+	if err != nil || len(ingresses) == 0 {
+		return "", err
+	}
+	if lb, ok := ingresses[0].(map[string]interface{}); ok {
+		if ip, ok := lb["ip"].(string); ok && ip != "" {
+			return ip, nil
+		}
+		if hostname, ok := lb["hostname"].(string); ok {
+			return hostname, nil
+		}
+	}
+	return "", nil
+}
+
+// newIngressRule returns an ingress rule routing the given path to a service in the schema of the given API version
+func newIngressRule(version schema.GroupVersion, host string, path ingressPath) map[string]interface{} {
+	var httpPath map[string]interface{}
+	if version.Group == "networking.k8s.io" && version.Version == "v1" {
+		port := map[string]interface{}{}
+		if name, ok := path.port.(string); ok {
+			port["name"] = name
+		} else {
+			port["number"] = path.port
+		}
+		httpPath = map[string]interface{}{
+			"path":     path.path,
+			"pathType": "Prefix",
+			"backend": map[string]interface{}{
+				"service": map[string]interface{}{
+					"name": path.service,
+					"port": port,
 				},
 			},
+		}
+	} else {
+		httpPath = map[string]interface{}{
+			"path": path.path,
+			"backend": map[string]interface{}{
+				"serviceName": path.service,
+				"servicePort": path.port,
+			},
+		}
+	}
+
+	rule := map[string]interface{}{
+		"http": map[string]interface{}{
+			"paths": []interface{}{httpPath},
 		},
 	}
-	_, err := c.kubeclient.ExtensionsV1beta1().Ingresses(c.clusterID).Create(ing)
-	return err
+	if host != "" {
+		rule["host"] = host
+	}
+	return rule
 }
@@ -0,0 +1,244 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package onit
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+)
+
+const (
+	// leaseConfigMap is the name of the ConfigMap in which device leases are stored
+	leaseConfigMap = "device-leases"
+
+	// leaseGracePeriod is the time for which a lease is held before the test job is created
+	leaseGracePeriod = time.Minute
+)
+
+const (
+	// AllDevices is the device count with which a test run reserves all the devices in the cluster
+	AllDevices = -1
+)
+
+// DeviceLease is a reservation of a device by a test run
+type DeviceLease struct {
+	TestID string
+	Time   time.Time
+}
+
+// GetDeviceLeases returns the active device leases in the cluster, keyed by device ID
+func (c *ClusterController) GetDeviceLeases() (map[string]DeviceLease, error) {
+	cm, err := c.getLeaseConfigMap()
+	if err != nil {
+		return nil, err
+	}
+	leases, err := decodeLeases(cm)
+	if err != nil {
+		return nil, err
+	}
+	for device, lease := range leases {
+		if !c.isLeaseActive(lease) {
+			delete(leases, device)
+		}
+	}
+	return leases, nil
+}
+
+// acquireDevices reserves devices for the given test, blocking until enough devices are available or done is closed.
+// If count is AllDevices, all the devices in the cluster are reserved. If more devices are requested than are
+// deployed in the cluster, acquireDevices waits for devices to be added until the timeout expires.
+func (c *ClusterController) acquireDevices(testID string, count int, timeout time.Duration, done <-chan struct{}) ([]string, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		devices, err := c.tryAcquireDevices(testID, count)
+		if err != nil {
+			return nil, err
+		} else if devices != nil {
+			return devices, nil
+		} else if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timed out waiting for %d free devices", count)
+		}
+		select {
+		case <-done:
+			return nil, errInterrupted
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+// tryAcquireDevices attempts to reserve devices for the given test, returning nil if not enough devices are available
+func (c *ClusterController) tryAcquireDevices(testID string, count int) ([]string, error) {
+	deviceIds, err := c.getDeviceIds()
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(deviceIds)
+
+	if count == AllDevices {
+		count = len(deviceIds)
+	} else if count < 0 {
+		return nil, fmt.Errorf("invalid device count %d", count)
+	}
+
+	var devices []string
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		devices = nil
+		cm, err := c.getLeaseConfigMap()
+		if err != nil {
+			return err
+		}
+		leases, err := decodeLeases(cm)
+		if err != nil {
+			return err
+		}
+
+		// Expire the leases of test runs that are no longer running
+		for device, lease := range leases {
+			if !c.isLeaseActive(lease) {
+				delete(leases, device)
+			}
+		}
+
+		devices = leaseDevices(leases, deviceIds, testID, count, time.Now())
+		if devices == nil {
+			return nil
+		}
+		return c.updateLeases(cm, leases)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return devices, nil
+}
+
+// leaseDevices adds leases for the given test to count of the given devices that are not leased by other tests,
+// returning the leased devices. If fewer than count devices are free, no leases are added and nil is returned.
+func leaseDevices(leases map[string]DeviceLease, deviceIds []string, testID string, count int, now time.Time) []string {
+	free := make([]string, 0, len(deviceIds))
+	for _, device := range deviceIds {
+		if _, ok := leases[device]; !ok {
+			free = append(free, device)
+		}
+	}
+	if len(free) < count {
+		return nil
+	}
+
+	devices := free[:count]
+	for _, device := range devices {
+		leases[device] = DeviceLease{
+			TestID: testID,
+			Time:   now,
+		}
+	}
+	return devices
+}
+
+// releaseDevices releases the devices reserved by the given test
+func (c *ClusterController) releaseDevices(testID string) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cm, err := c.getLeaseConfigMap()
+		if err != nil {
+			return err
+		}
+		leases, err := decodeLeases(cm)
+		if err != nil {
+			return err
+		}
+
+		released := false
+		for device, lease := range leases {
+			if lease.TestID == testID {
+				delete(leases, device)
+				released = true
+			}
+		}
+		if !released {
+			return nil
+		}
+		return c.updateLeases(cm, leases)
+	})
+}
+
+// isLeaseActive returns a boolean indicating whether the test holding the given lease is still running
+func (c *ClusterController) isLeaseActive(lease DeviceLease) bool {
+	job, err := c.kubeclient.BatchV1().Jobs(c.clusterID).Get(lease.TestID, metav1.GetOptions{})
+	if err != nil {
+		// Leases are acquired before the test job is created
+		return k8serrors.IsNotFound(err) && time.Since(lease.Time) < leaseGracePeriod
+	}
+	if TestStatus(job.Annotations["test-status"]) == TestCancelled {
+		return false
+	}
+	return job.Status.CompletionTime == nil && job.Status.Succeeded == 0 && job.Status.Failed == 0
+}
+
+// getLeaseConfigMap returns the ConfigMap in which device leases are stored, creating it if necessary
+func (c *ClusterController) getLeaseConfigMap() (*corev1.ConfigMap, error) {
+	cm, err := c.kubeclient.CoreV1().ConfigMaps(c.clusterID).Get(leaseConfigMap, metav1.GetOptions{})
+	if err == nil {
+		return cm, nil
+	} else if !k8serrors.IsNotFound(err) {
+		return nil, err
+	}
+
+	cm = &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      leaseConfigMap,
+			Namespace: c.clusterID,
+		},
+		Data: map[string]string{},
+	}
+	cm, err = c.kubeclient.CoreV1().ConfigMaps(c.clusterID).Create(cm)
+	if k8serrors.IsAlreadyExists(err) {
+		return c.kubeclient.CoreV1().ConfigMaps(c.clusterID).Get(leaseConfigMap, metav1.GetOptions{})
+	}
+	return cm, err
+}
+
+// updateLeases writes the given leases to the lease ConfigMap
+func (c *ClusterController) updateLeases(cm *corev1.ConfigMap, leases map[string]DeviceLease) error {
+	data := make(map[string]string)
+	for device, lease := range leases {
+		bytes, err := json.Marshal(lease)
+		if err != nil {
+			return err
+		}
+		data[device] = string(bytes)
+	}
+	cm.Data = data
+	_, err := c.kubeclient.CoreV1().ConfigMaps(c.clusterID).Update(cm)
+	return err
+}
+
+// decodeLeases decodes the device leases stored in the given ConfigMap
+func decodeLeases(cm *corev1.ConfigMap) (map[string]DeviceLease, error) {
+	leases := make(map[string]DeviceLease)
+	for device, value := range cm.Data {
+		lease := DeviceLease{}
+		if err := json.Unmarshal([]byte(value), &lease); err != nil {
+			return nil, err
+		}
+		leases[device] = lease
+	}
+	return leases, nil
+}
@@ -0,0 +1,88 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package onit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLeaseDevices(t *testing.T) {
+	now := time.Now()
+	deviceIds := []string{"sim-1", "sim-2", "sim-3"}
+
+	tests := []struct {
+		name     string
+		leased   map[string]string
+		count    int
+		expected []string
+	}{
+		{
+			name:     "no leases",
+			count:    2,
+			expected: []string{"sim-1", "sim-2"},
+		},
+		{
+			name:     "skips leased devices",
+			leased:   map[string]string{"sim-1": "test-1"},
+			count:    2,
+			expected: []string{"sim-2", "sim-3"},
+		},
+		{
+			name:     "all free devices",
+			leased:   map[string]string{"sim-2": "test-1"},
+			count:    2,
+			expected: []string{"sim-1", "sim-3"},
+		},
+		{
+			name:   "not enough free devices",
+			leased: map[string]string{"sim-1": "test-1", "sim-2": "test-2"},
+			count:  2,
+		},
+		{
+			name:  "more devices than deployed",
+			count: 4,
+		},
+		{
+			name:     "no devices",
+			leased:   map[string]string{"sim-1": "test-1", "sim-2": "test-1", "sim-3": "test-1"},
+			count:    0,
+			expected: []string{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			leases := make(map[string]DeviceLease)
+			for device, testID := range test.leased {
+				leases[device] = DeviceLease{TestID: testID, Time: now}
+			}
+
+			devices := leaseDevices(leases, deviceIds, "test-3", test.count, now)
+			assert.Equal(t, test.expected, devices)
+			if devices == nil {
+				assert.Len(t, leases, len(test.leased))
+			}
+			for _, device := range devices {
+				assert.Equal(t, DeviceLease{TestID: "test-3", Time: now}, leases[device])
+			}
+			for device, testID := range test.leased {
+				assert.Equal(t, testID, leases[device].TestID)
+			}
+		})
+	}
+}
@@ -0,0 +1,277 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package onit
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/onosproject/onos-test/pkg/onit/console"
+	"gopkg.in/yaml.v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// MatrixConfig provides the configuration for running tests across multiple cluster configurations
+type MatrixConfig struct {
+	Registry        string              `yaml:"registry"`
+	Preset          string              `yaml:"preset"`
+	PullPolicy      corev1.PullPolicy   `yaml:"pullPolicy"`
+	ImageTags       map[string][]string `yaml:"imageTags"`
+	ConfigNodes     []int               `yaml:"configNodes"`
+	TopoNodes       []int               `yaml:"topoNodes"`
+	Partitions      []int               `yaml:"partitions"`
+	PartitionSize   []int               `yaml:"partitionSize"`
+	Simulators      int                 `yaml:"simulators"`
+	SimulatorPreset string              `yaml:"simulatorPreset"`
+	Suites          []string            `yaml:"suites"`
+	Tests           []string            `yaml:"tests"`
+	Timeout         int                 `yaml:"timeout"`
+}
+
+// MatrixCluster is a single combination of cluster configurations in a matrix
+type MatrixCluster struct {
+	Name   string
+	Config *ClusterConfig
+}
+
+// MatrixResult contains the results of running the matrix tests on a single cluster
+type MatrixResult struct {
+	Cluster        MatrixCluster
+	Records        []TestRecord
+	ArtifactErrors map[string]error
+	Error          error
+}
+
+// LoadMatrixConfig loads a matrix configuration from the given file
+func LoadMatrixConfig(path string) (*MatrixConfig, error) {
+	bytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	matrix := &MatrixConfig{}
+	if err := yaml.Unmarshal(bytes, matrix); err != nil {
+		return nil, err
+	}
+	if len(matrix.Suites) == 0 && len(matrix.Tests) == 0 {
+		return nil, errors.New("matrix must specify at least one suite or test")
+	}
+	return matrix, nil
+}
+
+// GetRuns returns the names of the test runs to perform on each cluster
+func (m *MatrixConfig) GetRuns() []string {
+	runs := make([]string, 0, len(m.Suites)+1)
+	runs = append(runs, m.Suites...)
+	if len(m.Tests) > 0 {
+		runs = append(runs, strings.Join(m.Tests, ","))
+	}
+	return runs
+}
+
+// GetClusters returns the cluster configurations for every combination in the matrix
+func (m *MatrixConfig) GetClusters() []MatrixCluster {
+	clusters := []MatrixCluster{
+		{
+			Config: &ClusterConfig{
+				Registry:   m.Registry,
+				Preset:     m.Preset,
+				PullPolicy: m.PullPolicy,
+				ImageTags:  make(map[string]string),
+			},
+		},
+	}
+	if clusters[0].Config.Preset == "" {
+		clusters[0].Config.Preset = "default"
+	}
+	if clusters[0].Config.PullPolicy == "" {
+		clusters[0].Config.PullPolicy = corev1.PullIfNotPresent
+	}
+
+	// Expand the image tags in a stable order
+	components := make([]string, 0, len(m.ImageTags))
+	for component := range m.ImageTags {
+		components = append(components, component)
+	}
+	sort.Strings(components)
+	for _, component := range components {
+		clusters = expandMatrix(clusters, len(m.ImageTags[component]), func(config *ClusterConfig, i int) string {
+			config.ImageTags[component] = m.ImageTags[component][i]
+			return fmt.Sprintf("%s=%s", component, m.ImageTags[component][i])
+		})
+	}
+
+	clusters = expandMatrixInts(clusters, "configNodes", m.ConfigNodes, func(config *ClusterConfig, value int) {
+		config.ConfigNodes = value
+	})
+	clusters = expandMatrixInts(clusters, "topoNodes", m.TopoNodes, func(config *ClusterConfig, value int) {
+		config.TopoNodes = value
+	})
+	clusters = expandMatrixInts(clusters, "partitions", m.Partitions, func(config *ClusterConfig, value int) {
+		config.Partitions = value
+	})
+	clusters = expandMatrixInts(clusters, "partitionSize", m.PartitionSize, func(config *ClusterConfig, value int) {
+		config.PartitionSize = value
+	})
+	return clusters
+}
+
+// expandMatrixInts expands the given clusters by each of the given values, defaulting to a single value of 1
+func expandMatrixInts(clusters []MatrixCluster, name string, values []int, set func(*ClusterConfig, int)) []MatrixCluster {
+	if len(values) == 0 {
+		for _, cluster := range clusters {
+			set(cluster.Config, 1)
+		}
+		return clusters
+	}
+	return expandMatrix(clusters, len(values), func(config *ClusterConfig, i int) string {
+		set(config, values[i])
+		return fmt.Sprintf("%s=%d", name, values[i])
+	})
+}
+
+// expandMatrix returns the product of the given clusters and n values applied by the given function
+func expandMatrix(clusters []MatrixCluster, n int, apply func(*ClusterConfig, int) string) []MatrixCluster {
+	if n == 0 {
+		return clusters
+	}
+	expanded := make([]MatrixCluster, 0, len(clusters)*n)
+	for _, cluster := range clusters {
+		for i := 0; i < n; i++ {
+			config := *cluster.Config
+			config.ImageTags = make(map[string]string)
+			for component, tag := range cluster.Config.ImageTags {
+				config.ImageTags[component] = tag
+			}
+			label := apply(&config, i)
+			name := label
+			if cluster.Name != "" {
+				name = cluster.Name + "," + label
+			}
+			expanded = append(expanded, MatrixCluster{
+				Name:   name,
+				Config: &config,
+			})
+		}
+	}
+	return expanded
+}
+
+// RunMatrix sets up a cluster for each of the given matrix clusters, runs the matrix tests on all clusters in
+// parallel and tears the clusters down
+func (c *Controller) RunMatrix(matrixID string, matrix *MatrixConfig, clusters []MatrixCluster) ([]MatrixResult, console.ErrorStatus) {
+	results := make([]MatrixResult, len(clusters))
+	controllers := make([]*ClusterController, len(clusters))
+
+	// Tear down all the clusters that were created once the tests are complete
+	defer func() {
+		for i, cluster := range controllers {
+			if cluster != nil {
+				c.DeleteCluster(fmt.Sprintf("%s-%d", matrixID, i))
+			}
+		}
+	}()
+
+	// Set up the clusters one at a time
+	for i, cluster := range clusters {
+		results[i].Cluster = cluster
+		clusterID := fmt.Sprintf("%s-%d", matrixID, i)
+		c.status.Start(fmt.Sprintf("Creating matrix cluster %s (%s)", clusterID, cluster.Name))
+		controller, status := c.NewCluster(clusterID, cluster.Config)
+		if status.Failed() {
+			return results, status
+		}
+		controllers[i] = controller
+		if status := controller.Setup(); status.Failed() {
+			return results, status
+		}
+		for j := 0; j < matrix.Simulators; j++ {
+			config := &SimulatorConfig{
+				Config: matrix.SimulatorPreset,
+			}
+			if config.Config == "" {
+				config.Config = "default"
+			}
+			if status := controller.AddSimulator(fmt.Sprintf("device-%d", j+1), config); status.Failed() {
+				return results, status
+			}
+		}
+	}
+
+	// Run the tests on all clusters in parallel
+	c.status.Start(fmt.Sprintf("Running tests on %d clusters", len(clusters)))
+	timeout := time.Duration(matrix.Timeout) * time.Second
+	wg := &sync.WaitGroup{}
+	for i := range controllers {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			cluster := controllers[i]
+			results[i].Records = make([]TestRecord, 0)
+			results[i].ArtifactErrors = make(map[string]error)
+			for j, run := range matrix.GetRuns() {
+				var args []string
+				if j < len(matrix.Suites) {
+					args = []string{string(TestTypeSuite), run}
+				} else {
+					args = append([]string{string(TestTypeTest)}, matrix.Tests...)
+				}
+				record, err := cluster.ExecuteTests(fmt.Sprintf("%s-%d", cluster.clusterID, j), args, AllDevices, timeout)
+				if err != nil {
+					results[i].Error = err
+					return
+				}
+				results[i].Records = append(results[i].Records, record)
+
+				// If the tests failed, capture the logs of the cluster's nodes before the cluster is torn down
+				if record.Status == TestFailed {
+					if err := cluster.captureArtifacts(record.TestID); err != nil {
+						results[i].ArtifactErrors[record.TestID] = err
+					}
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	var err error
+	for _, result := range results {
+		if result.Error != nil {
+			err = result.Error
+			break
+		}
+	}
+	if err != nil {
+		c.status.Fail(err)
+	} else {
+		c.status.Succeed()
+	}
+
+	// Artifact failures are reported without discarding the records of the failed test runs
+	for _, result := range results {
+		for _, record := range result.Records {
+			if err, ok := result.ArtifactErrors[record.TestID]; ok {
+				c.status.Start("Capturing test artifacts for " + record.TestID)
+				c.status.Fail(err)
+			}
+		}
+	}
+	return results, c.status
+}
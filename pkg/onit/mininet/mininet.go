@@ -0,0 +1,206 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package mininet drives the Mininet CLI of a stratum network pod.
+//
+// Mininet runs as the main process of the network pod and reads CLI commands from the container's stdin.
+// Commands are written to the stdin of the Mininet process from a command executed in the pod, and the
+// resulting state is written by Mininet to a file in the pod from which it's read back by the same command.
+package mininet
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// stateTimeout is the number of seconds to wait for Mininet to report the state of a link or switch
+	stateTimeout = 10
+)
+
+var (
+	// switchTimeout is the time to wait for a switch to start or stop serving gRPC
+	switchTimeout = 30 * time.Second
+
+	// switchPollInterval is the interval at which the state of a starting or stopping switch is checked
+	switchPollInterval = time.Second
+)
+
+// LinkState is the state of a link
+type LinkState string
+
+const (
+	// LinkUp indicates the interfaces at both ends of the link are up
+	LinkUp LinkState = "up"
+
+	// LinkDown indicates an interface at either end of the link is down
+	LinkDown LinkState = "down"
+)
+
+// ParseLinkState parses a link state from an up or down string
+func ParseLinkState(value string) (LinkState, error) {
+	switch state := LinkState(strings.ToLower(value)); state {
+	case LinkUp, LinkDown:
+		return state, nil
+	default:
+		return "", fmt.Errorf("invalid link state %s; must be up or down", value)
+	}
+}
+
+// SwitchAction is an action to take on a switch
+type SwitchAction string
+
+const (
+	// SwitchStart starts a stopped switch
+	SwitchStart SwitchAction = "start"
+
+	// SwitchStop stops a running switch
+	SwitchStop SwitchAction = "stop"
+)
+
+// ParseSwitchAction parses a switch action from a start or stop string
+func ParseSwitchAction(value string) (SwitchAction, error) {
+	switch action := SwitchAction(strings.ToLower(value)); action {
+	case SwitchStart, SwitchStop:
+		return action, nil
+	default:
+		return "", fmt.Errorf("invalid switch action %s; must be start or stop", value)
+	}
+}
+
+// SwitchState is the state of a switch
+type SwitchState string
+
+const (
+	// SwitchRunning indicates the switch is serving gRPC
+	SwitchRunning SwitchState = "running"
+
+	// SwitchStopped indicates the switch is not serving gRPC
+	SwitchStopped SwitchState = "stopped"
+)
+
+// Executor executes a command in the network pod and returns its output
+type Executor func(command []string) ([]byte, error)
+
+// CLI is a client for the Mininet CLI of a network
+type CLI struct {
+	execute Executor
+}
+
+// NewCLI returns a new Mininet CLI client that executes commands in the network pod using the given executor
+func NewCLI(execute Executor) *CLI {
+	return &CLI{
+		execute: execute,
+	}
+}
+
+// SetLink brings the link between the given nodes up or down and returns the resulting state of the link
+func (c *CLI) SetLink(source string, target string, state LinkState) (LinkState, error) {
+	return c.runLink(fmt.Sprintf("link %s %s %s", source, target, state), source, target)
+}
+
+// GetLink returns the state of the link between the given nodes
+func (c *CLI) GetLink(source string, target string) (LinkState, error) {
+	return c.runLink("", source, target)
+}
+
+// runLink runs the given Mininet CLI command and returns the resulting state of the link between the given nodes
+func (c *CLI) runLink(command string, source string, target string) (LinkState, error) {
+	value, err := c.run(command, linkStateExpr(source, target))
+	if err != nil {
+		return "", err
+	} else if value == "" {
+		return "", fmt.Errorf("no link between %s and %s", source, target)
+	}
+	return LinkState(value), nil
+}
+
+// SetSwitch starts or stops the given switch, waiting for it to start or stop serving gRPC on the given port,
+// and returns the resulting state of the switch. An error is returned if the switch does not reach the state
+// before the switch timeout.
+func (c *CLI) SetSwitch(name string, port int, action SwitchAction) (SwitchState, error) {
+	state, err := c.run(fmt.Sprintf("switch %s %s", name, action), switchStateExpr(port))
+	if err != nil {
+		return "", err
+	}
+
+	expected := SwitchRunning
+	if action == SwitchStop {
+		expected = SwitchStopped
+	}
+	deadline := time.Now().Add(switchTimeout)
+	for SwitchState(state) != expected {
+		if !time.Now().Before(deadline) {
+			return "", fmt.Errorf("timed out after %s waiting for switch %s to %s", switchTimeout, name, action)
+		}
+		time.Sleep(switchPollInterval)
+		if state, err = c.run("", switchStateExpr(port)); err != nil {
+			return "", err
+		}
+	}
+	return SwitchState(state), nil
+}
+
+// GetSwitch returns the state of the switch serving gRPC on the given port
+func (c *CLI) GetSwitch(port int) (SwitchState, error) {
+	state, err := c.run("", switchStateExpr(port))
+	if err != nil {
+		return "", err
+	}
+	return SwitchState(state), nil
+}
+
+// run runs the given Mininet CLI command, if any, followed by a command that writes the value of the given
+// Python expression to a state file, and returns the value read from the file
+func (c *CLI) run(command string, stateExpr string) (string, error) {
+	stateFile := fmt.Sprintf("/tmp/onit-%s", uuid.New().String())
+	lines := []string{
+		fmt.Sprintf("py open('%s.tmp', 'w').write(%s)", stateFile, stateExpr),
+		fmt.Sprintf("py __import__('os').rename('%s.tmp', '%s')", stateFile, stateFile),
+	}
+	if command != "" {
+		lines = append([]string{command}, lines...)
+	}
+
+	// Write the commands to the stdin of the Mininet process and wait for the state file to be written
+	script := fmt.Sprintf(`printf '%%s\n' "$@" > /proc/1/fd/0
+for i in $(seq %d); do
+  if [ -f %[2]s ]; then cat %[2]s; rm -f %[2]s; exit 0; fi
+  sleep 1
+done
+exit 1`, stateTimeout, stateFile)
+	output, err := c.execute(append([]string{"/bin/sh", "-c", script, "sh"}, lines...))
+	if err != nil {
+		return "", fmt.Errorf("mininet command failed: %v", err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// linkStateExpr returns a Python expression that evaluates to the state of the link between the given nodes,
+// or to an empty string if the nodes are not linked
+func linkStateExpr(source string, target string) string {
+	intfs := fmt.Sprintf("net.get('%s').connectionsTo(net.get('%s'))", source, target)
+	return fmt.Sprintf("('%s' if all(a.isUp() and b.isUp() for a, b in %s) else '%s') if %s else ''",
+		LinkUp, intfs, LinkDown, intfs)
+}
+
+// switchStateExpr returns a Python expression that evaluates to the state of the switch serving gRPC on the given
+// port. The socket used to probe the port is closed once the connection has been attempted.
+func switchStateExpr(port int) string {
+	connect := fmt.Sprintf("(lambda s: (s.connect_ex(('127.0.0.1', %d)), s.close())[0])(__import__('socket').socket())", port)
+	return fmt.Sprintf("'%s' if %s == 0 else '%s'", SwitchRunning, connect, SwitchStopped)
+}
@@ -0,0 +1,182 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mininet
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLinkState(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected LinkState
+		valid    bool
+	}{
+		{value: "up", expected: LinkUp, valid: true},
+		{value: "DOWN", expected: LinkDown, valid: true},
+		{value: "Up", expected: LinkUp, valid: true},
+		{value: "", valid: false},
+		{value: "sideways", valid: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.value, func(t *testing.T) {
+			state, err := ParseLinkState(test.value)
+			if test.valid {
+				assert.NoError(t, err)
+				assert.Equal(t, test.expected, state)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestParseSwitchAction(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected SwitchAction
+		valid    bool
+	}{
+		{value: "start", expected: SwitchStart, valid: true},
+		{value: "STOP", expected: SwitchStop, valid: true},
+		{value: "", valid: false},
+		{value: "restart", valid: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.value, func(t *testing.T) {
+			action, err := ParseSwitchAction(test.value)
+			if test.valid {
+				assert.NoError(t, err)
+				assert.Equal(t, test.expected, action)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestStateExprs(t *testing.T) {
+	assert.Equal(t,
+		"('up' if all(a.isUp() and b.isUp() for a, b in net.get('s1').connectionsTo(net.get('h1'))) else 'down') "+
+			"if net.get('s1').connectionsTo(net.get('h1')) else ''",
+		linkStateExpr("s1", "h1"))
+	assert.Equal(t,
+		"'running' if (lambda s: (s.connect_ex(('127.0.0.1', 50001)), s.close())[0])(__import__('socket').socket()) == 0 else 'stopped'",
+		switchStateExpr(50001))
+}
+
+// stateFilePattern matches the commands that write a state expression to a state file and move it into place
+var stateFilePattern = regexp.MustCompile(`^py open\('(/tmp/onit-[0-9a-f-]+)\.tmp', 'w'\)\.write\((.+)\)$`)
+
+func TestSetLink(t *testing.T) {
+	var commands [][]string
+	cli := NewCLI(func(command []string) ([]byte, error) {
+		commands = append(commands, command)
+		return []byte("down\n"), nil
+	})
+
+	state, err := cli.SetLink("s1", "s2", LinkDown)
+	assert.NoError(t, err)
+	assert.Equal(t, LinkDown, state)
+
+	// The Mininet commands are passed as arguments to a script that writes them to the stdin of the Mininet process
+	assert.Len(t, commands, 1)
+	command := commands[0]
+	assert.Equal(t, []string{"/bin/sh", "-c"}, command[:2])
+	assert.Contains(t, command[2], `printf '%s\n' "$@" > /proc/1/fd/0`)
+	assert.Contains(t, command[2], "for i in $(seq 10); do")
+	assert.Equal(t, "sh", command[3])
+
+	lines := command[4:]
+	assert.Len(t, lines, 3)
+	assert.Equal(t, "link s1 s2 down", lines[0])
+	match := stateFilePattern.FindStringSubmatch(lines[1])
+	if assert.NotNil(t, match) {
+		assert.Equal(t, linkStateExpr("s1", "s2"), match[2])
+		assert.Equal(t, "py __import__('os').rename('"+match[1]+".tmp', '"+match[1]+"')", lines[2])
+		assert.Contains(t, command[2], "cat "+match[1])
+	}
+}
+
+func TestGetLinkNotLinked(t *testing.T) {
+	var commands [][]string
+	cli := NewCLI(func(command []string) ([]byte, error) {
+		commands = append(commands, command)
+		return []byte("\n"), nil
+	})
+
+	_, err := cli.GetLink("s1", "s3")
+	assert.Error(t, err)
+
+	// Getting the state of a link runs no Mininet command before writing the state
+	assert.Len(t, commands, 1)
+	assert.Len(t, commands[0][4:], 2)
+}
+
+func TestSetSwitch(t *testing.T) {
+	switchPollInterval = time.Millisecond
+	defer func() {
+		switchPollInterval = time.Second
+	}()
+
+	states := []string{"running", "running", "stopped"}
+	var commands [][]string
+	cli := NewCLI(func(command []string) ([]byte, error) {
+		commands = append(commands, command)
+		state := states[0]
+		states = states[1:]
+		return []byte(state), nil
+	})
+
+	state, err := cli.SetSwitch("s1", 50001, SwitchStop)
+	assert.NoError(t, err)
+	assert.Equal(t, SwitchStopped, state)
+	assert.Len(t, commands, 3)
+	assert.Equal(t, "switch s1 stop", commands[0][4])
+	assert.Len(t, commands[1][4:], 2)
+}
+
+func TestSetSwitchTimeout(t *testing.T) {
+	switchTimeout = 10 * time.Millisecond
+	switchPollInterval = time.Millisecond
+	defer func() {
+		switchTimeout = 30 * time.Second
+		switchPollInterval = time.Second
+	}()
+
+	cli := NewCLI(func(command []string) ([]byte, error) {
+		return []byte("stopped"), nil
+	})
+
+	state, err := cli.SetSwitch("s1", 50001, SwitchStart)
+	assert.Error(t, err)
+	assert.Equal(t, SwitchState(""), state)
+}
+
+func TestSetSwitchError(t *testing.T) {
+	cli := NewCLI(func(command []string) ([]byte, error) {
+		return nil, errors.New("exec failed")
+	})
+
+	_, err := cli.SetSwitch("s1", 50001, SwitchStart)
+	assert.Error(t, err)
+}
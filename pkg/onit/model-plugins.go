@@ -0,0 +1,106 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package onit
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+)
+
+const (
+	// modelPluginsPath is the path at which model plugins copied from images are mounted in onos-config nodes
+	modelPluginsPath = "/usr/local/lib/plugins"
+
+	// configPort is the gRPC port of the onos-config service
+	configPort = 5150
+)
+
+// defaultModelPlugins is the list of model plugins loaded by onos-config unless otherwise configured
+var defaultModelPlugins = []ModelPlugin{
+	{Type: "testdevice", Version: "1.0.0"},
+	{Type: "testdevice", Version: "2.0.0"},
+	{Type: "devicesim", Version: "1.0.0"},
+	{Type: "stratum", Version: "1.0.0"},
+}
+
+// GetDefaultModelPlugins returns the model plugins loaded by onos-config unless otherwise configured
+func GetDefaultModelPlugins() []ModelPlugin {
+	plugins := make([]ModelPlugin, len(defaultModelPlugins))
+	copy(plugins, defaultModelPlugins)
+	return plugins
+}
+
+// ParseModelPlugin parses a model plugin from a type:version string, optionally followed by =<path> to load
+// the plugin from a path in the onos-config image or =<image>:<path> to copy the plugin from a path in an image
+func ParseModelPlugin(value string) (ModelPlugin, error) {
+	plugin := ModelPlugin{}
+	if i := strings.Index(value, "="); i >= 0 {
+		source := value[i+1:]
+		if strings.HasPrefix(source, "/") {
+			plugin.Path = source
+		} else if j := strings.Index(source, ":/"); j >= 0 {
+			plugin.Image = source[:j]
+			plugin.Path = source[j+1:]
+		} else {
+			plugin.Image = source
+		}
+		value = value[:i]
+	}
+
+	parts := strings.Split(value, ":")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return plugin, fmt.Errorf("invalid model plugin %s; must be of the form type:version[=path|image:path]", value)
+	}
+	plugin.Type = parts[0]
+	plugin.Version = parts[1]
+	return plugin, plugin.validate()
+}
+
+// ModelInfo is a device model supported by onos-config
+type ModelInfo struct {
+	Name         string `yaml:"name"`
+	Organization string `yaml:"organization"`
+	Version      string `yaml:"version"`
+}
+
+// GetModels returns the device models loaded by onos-config, as reported by its gNMI capabilities
+func (c *ClusterController) GetModels() ([]ModelInfo, error) {
+	conn, stop, err := c.dialService(OnosConfig, "onos-config", configPort)
+	if err != nil {
+		return nil, err
+	}
+	defer close(stop)
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), dialTimeout)
+	defer cancel()
+	response, err := gnmi.NewGNMIClient(conn).Capabilities(ctx, &gnmi.CapabilityRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	models := make([]ModelInfo, len(response.SupportedModels))
+	for i, model := range response.SupportedModels {
+		models[i] = ModelInfo{
+			Name:         model.Name,
+			Organization: model.Organization,
+			Version:      model.Version,
+		}
+	}
+	return models, nil
+}
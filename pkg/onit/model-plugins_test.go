@@ -0,0 +1,76 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package onit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseModelPlugin(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected ModelPlugin
+		valid    bool
+	}{
+		{
+			value:    "devicesim:1.0.0",
+			expected: ModelPlugin{Type: "devicesim", Version: "1.0.0"},
+			valid:    true,
+		},
+		{
+			value:    "mydevice:1.0.0=/usr/local/lib/mydevice.so.1.0.0",
+			expected: ModelPlugin{Type: "mydevice", Version: "1.0.0", Path: "/usr/local/lib/mydevice.so.1.0.0"},
+			valid:    true,
+		},
+		{
+			value: "mydevice:1.0.0=myorg/mydevice-plugin:latest:/usr/local/lib/mydevice.so.1.0.0",
+			expected: ModelPlugin{
+				Type:    "mydevice",
+				Version: "1.0.0",
+				Image:   "myorg/mydevice-plugin:latest",
+				Path:    "/usr/local/lib/mydevice.so.1.0.0",
+			},
+			valid: true,
+		},
+		{
+			value: "mydevice:1.0.0=localhost:5000/mydevice-plugin:/plugins/mydevice.so",
+			expected: ModelPlugin{
+				Type:    "mydevice",
+				Version: "1.0.0",
+				Image:   "localhost:5000/mydevice-plugin",
+				Path:    "/plugins/mydevice.so",
+			},
+			valid: true,
+		},
+		{value: "mydevice:1.0.0=myorg/mydevice-plugin:latest", valid: false},
+		{value: "mydevice", valid: false},
+		{value: "mydevice:", valid: false},
+		{value: ":1.0.0", valid: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.value, func(t *testing.T) {
+			plugin, err := ParseModelPlugin(test.value)
+			if test.valid {
+				assert.NoError(t, err)
+				assert.Equal(t, test.expected, plugin)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
@@ -15,11 +15,13 @@
 package onit
 
 import (
-	"bytes"
+	"errors"
+	"fmt"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/onosproject/onos-test/pkg/onit/mininet"
 	"gopkg.in/yaml.v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -27,7 +29,13 @@ import (
 )
 
 var (
-	topoOption = "--topo"
+	topoOption   = "--topo"
+	customOption = "--custom"
+)
+
+const (
+	// stratumPort is the gRPC port of the first stratum switch in a network
+	stratumPort = 50001
 )
 
 // TopoType topology type
@@ -40,10 +48,14 @@ const (
 	Tree
 	// Single node topology
 	Single
+	// Torus torus topology type
+	Torus
+	// Custom topology loaded from a topology file
+	Custom
 )
 
 func (d TopoType) String() string {
-	return [...]string{"linear", "tree", "single"}[d]
+	return [...]string{"linear", "tree", "single", "torus", "custom"}[d]
 }
 
 // GetNetworks returns a list of networks deployed in the cluster
@@ -63,6 +75,117 @@ func (c *ClusterController) GetNetworks() ([]string, error) {
 	return networks, nil
 }
 
+// NetworkSwitch is a stratum switch in a network
+type NetworkSwitch struct {
+	Name   string `json:"name"`
+	Device string `json:"device"`
+	Port   int    `json:"port"`
+}
+
+// NetworkInfo describes the topology of a network deployed in the cluster
+type NetworkInfo struct {
+	Name     string          `json:"name"`
+	Type     string          `json:"type"`
+	Switches []NetworkSwitch `json:"switches"`
+	Hosts    []string        `json:"hosts"`
+	Links    []Link          `json:"links"`
+}
+
+// GetNetwork returns the topology of the given network as derived from the configuration with which it was added
+func (c *ClusterController) GetNetwork(name string) (*NetworkInfo, error) {
+	cm, err := c.kubeclient.CoreV1().ConfigMaps(c.clusterID).Get(name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	config, err := decodeNetworkConfig(cm.BinaryData["config"])
+	if err != nil {
+		return nil, err
+	}
+
+	ports := config.Topology.getSwitchPorts()
+	switches := make([]NetworkSwitch, len(config.Topology.Switches))
+	for i, s := range config.Topology.Switches {
+		switches[i] = NetworkSwitch{
+			Name:   s,
+			Device: getSwitchDeviceName(name, s),
+			Port:   ports[s],
+		}
+	}
+	return &NetworkInfo{
+		Name:     name,
+		Type:     config.TopoType.String(),
+		Switches: switches,
+		Hosts:    config.Topology.Hosts,
+		Links:    config.Topology.Links,
+	}, nil
+}
+
+// hasLink returns whether the network has a link between the given nodes
+func (n *NetworkInfo) hasLink(source string, target string) bool {
+	for _, link := range n.Links {
+		if (link.Source == source && link.Target == target) || (link.Source == target && link.Target == source) {
+			return true
+		}
+	}
+	return false
+}
+
+// getSwitch returns the switch with the given name
+func (n *NetworkInfo) getSwitch(name string) (NetworkSwitch, bool) {
+	for _, s := range n.Switches {
+		if s.Name == name {
+			return s, true
+		}
+	}
+	return NetworkSwitch{}, false
+}
+
+// getNetworkCLI returns a client for the Mininet CLI running in the given network's pod
+func (c *ClusterController) getNetworkCLI(name string) (*mininet.CLI, error) {
+	pod, err := c.kubeclient.CoreV1().Pods(c.clusterID).Get(name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return mininet.NewCLI(func(command []string) ([]byte, error) {
+		return c.executeOutput(*pod, command)
+	}), nil
+}
+
+// setNetworkLink brings the link between the given nodes of a network up or down
+func (c *ClusterController) setNetworkLink(name string, source string, target string, state mininet.LinkState) (mininet.LinkState, error) {
+	network, err := c.GetNetwork(name)
+	if err != nil {
+		return "", err
+	}
+	if !network.hasLink(source, target) {
+		return "", fmt.Errorf("network %s has no link between %s and %s", name, source, target)
+	}
+
+	cli, err := c.getNetworkCLI(name)
+	if err != nil {
+		return "", err
+	}
+	return cli.SetLink(source, target, state)
+}
+
+// setNetworkSwitch starts or stops the given switch of a network
+func (c *ClusterController) setNetworkSwitch(name string, switchName string, action mininet.SwitchAction) (mininet.SwitchState, error) {
+	network, err := c.GetNetwork(name)
+	if err != nil {
+		return "", err
+	}
+	s, ok := network.getSwitch(switchName)
+	if !ok {
+		return "", fmt.Errorf("network %s has no switch %s", name, switchName)
+	}
+
+	cli, err := c.getNetworkCLI(name)
+	if err != nil {
+		return "", err
+	}
+	return cli.SetSwitch(s.Name, s.Port, action)
+}
+
 // setupNetwork creates a network of stratum devices required for the test
 func (c *ClusterController) setupNetwork(name string, config *NetworkConfig) error {
 
@@ -102,6 +225,9 @@ func (c *ClusterController) createNetworkConfigMap(name string, config *NetworkC
 			"config": configByte,
 		},
 	}
+	if config.TopoType == Custom {
+		cm.BinaryData[customTopoFile] = config.Topology.newCustomTopologyScript()
+	}
 	_, err = c.kubeclient.CoreV1().ConfigMaps(c.clusterID).Create(cm)
 	if err != nil {
 		return err
@@ -114,6 +240,10 @@ func (c *ClusterController) createNetworkConfigMap(name string, config *NetworkC
 func (c *ClusterController) createNetworkPod(name string, config *NetworkConfig) error {
 
 	var isPrivileged = true
+	args := config.MininetOptions
+	if config.TopoType == Custom {
+		args = append(append([]string{}, args...), customOption, "/etc/simulator/configs/"+customTopoFile, topoOption, customTopoName)
+	}
 	pod := &corev1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      name,
@@ -130,17 +260,17 @@ func (c *ClusterController) createNetworkPod(name string, config *NetworkConfig)
 					Image:           c.imageName("opennetworking/mn-stratum", c.config.ImageTags["stratum"]),
 					ImagePullPolicy: c.config.PullPolicy,
 					Stdin:           true,
-					Args:            config.MininetOptions,
+					Args:            args,
 					Ports: []corev1.ContainerPort{
 						{
 							Name:          "stratum",
-							ContainerPort: 50001,
+							ContainerPort: stratumPort,
 						},
 					},
 					ReadinessProbe: &corev1.Probe{
 						Handler: corev1.Handler{
 							TCPSocket: &corev1.TCPSocketAction{
-								Port: intstr.FromInt(50001),
+								Port: intstr.FromInt(stratumPort),
 							},
 						},
 						InitialDelaySeconds: 5,
@@ -149,7 +279,7 @@ func (c *ClusterController) createNetworkPod(name string, config *NetworkConfig)
 					LivenessProbe: &corev1.Probe{
 						Handler: corev1.Handler{
 							TCPSocket: &corev1.TCPSocketAction{
-								Port: intstr.FromInt(50001),
+								Port: intstr.FromInt(stratumPort),
 							},
 						},
 						InitialDelaySeconds: 15,
@@ -201,43 +331,139 @@ func (c *ClusterController) awaitNetworkReady(name string) error {
 	}
 }
 
-// ParseMininetOptions parses mininet options and initialize the network configuration accordingly
-func ParseMininetOptions(config *NetworkConfig) {
-	mininetOptions := config.MininetOptions
+// ParseMininetOptions parses mininet options and initialize the network configuration accordingly.
+// If the configuration already has a custom topology, the options must not specify a topology.
+func ParseMininetOptions(config *NetworkConfig) error {
+	topoArg := ""
+	for i, option := range config.MininetOptions {
+		switch {
+		case option == topoOption && i+1 < len(config.MininetOptions):
+			topoArg = config.MininetOptions[i+1]
+		case strings.HasPrefix(option, topoOption+"="):
+			topoArg = strings.TrimPrefix(option, topoOption+"=")
+		case option == customOption || strings.HasPrefix(option, customOption+"="):
+			return errors.New("custom Mininet topology scripts are not supported; use a topology file instead")
+		}
+	}
+
+	if config.TopoType == Custom {
+		if topoArg != "" {
+			return errors.New("a topology file cannot be combined with the --topo option")
+		}
+		if err := config.Topology.validate(); err != nil {
+			return err
+		}
+		config.NumDevices = len(config.Topology.Switches)
+		return nil
+	}
+
+	topology, topoType, err := parseTopology(topoArg)
+	if err != nil {
+		return err
+	}
+	config.Topology = *topology
+	config.TopoType = topoType
+	config.NumDevices = len(topology.Switches)
+	return nil
+}
+
+// parseTopology parses a Mininet topology argument of the form <type>[,<param>...]
+func parseTopology(topoArg string) (*Topology, TopoType, error) {
+	if topoArg == "" {
+		return newSingleTopology(2), Single, nil
+	}
 
-	if len(mininetOptions) != 0 {
-		if strings.Compare(mininetOptions[0], topoOption) == 0 {
-			topoType := strings.Split(mininetOptions[1], ",")
-			if strings.Compare(topoType[0], Linear.String()) == 0 {
+	parts := strings.Split(topoArg, ",")
+	params := make([]int, 0, len(parts)-1)
+	for _, part := range parts[1:] {
+		// Mininet parameters may be passed as key=value, in which case the value is used positionally
+		if i := strings.Index(part, "="); i >= 0 {
+			part = part[i+1:]
+		}
+		param, err := strconv.Atoi(part)
+		if err != nil || param < 1 {
+			return nil, 0, fmt.Errorf("invalid topology parameter %s in %s", part, topoArg)
+		}
+		params = append(params, param)
+	}
+	param := func(i int, def int) int {
+		if i < len(params) {
+			return params[i]
+		}
+		return def
+	}
 
-				config.NumDevices, _ = strconv.Atoi(topoType[1])
-				config.TopoType = Linear
-			}
+	switch parts[0] {
+	case Single.String(), "minimal":
+		return newSingleTopology(param(0, 2)), Single, nil
+	case Linear.String():
+		return newLinearTopology(param(0, 2), param(1, 1)), Linear, nil
+	case Tree.String():
+		return newTreeTopology(param(0, 1), param(1, 2)), Tree, nil
+	case Torus.String():
+		if len(params) < 2 {
+			return nil, 0, fmt.Errorf("torus topology requires x and y dimensions")
 		}
-	} else {
-		config.NumDevices = 1
-		config.TopoType = Single
+		if params[0] < 3 || params[1] < 3 {
+			return nil, 0, fmt.Errorf("torus topology dimensions must be 3x3 or greater")
+		}
+		return newTorusTopology(params[0], params[1], param(2, 1)), Torus, nil
+	default:
+		return nil, 0, fmt.Errorf("unsupported topology %s; must be one of %s, %s, %s or %s", parts[0], Single, Linear, Tree, Torus)
+	}
+}
 
+// getNetworkDevices returns the onos-topo devices for the switches in the given network
+func getNetworkDevices(name string, config *NetworkConfig) []*Device {
+	ports := config.Topology.getSwitchPorts()
+	devices := make([]*Device, 0, len(config.Topology.Switches))
+	for _, s := range config.Topology.Switches {
+		deviceName := getSwitchDeviceName(name, s)
+		devices = append(devices, &Device{
+			ID:      deviceName,
+			Address: fmt.Sprintf("%s:%d", deviceName, ports[s]),
+			Type:    "Stratum",
+			TLS: DeviceTLS{
+				Plain: true,
+			},
+		})
 	}
+	return devices
 }
 
-// createNetworkService creates a network service
-func (c *ClusterController) createNetworkService(name string, config *NetworkConfig) error {
+// getSwitchDeviceName returns the device and service name of the given switch in the given network
+func getSwitchDeviceName(network string, switchName string) string {
+	return fmt.Sprintf("%s-%s", network, switchName)
+}
 
-	var port int32 = 50001
+// decodeNetworkConfig decodes a network configuration stored in a network ConfigMap
+func decodeNetworkConfig(data []byte) (*NetworkConfig, error) {
+	config := &NetworkConfig{}
+	if err := yaml.Unmarshal(data, config); err != nil {
+		return nil, err
+	}
 
-	for i := 0; i < config.NumDevices; i++ {
-		var buf bytes.Buffer
-		buf.WriteString(name)
-		buf.WriteString("-s")
-		buf.WriteString(strconv.Itoa(i))
-		serviceName := buf.String()
+	// Networks added before topologies were stored in the configuration have only a device count
+	if len(config.Topology.Switches) == 0 {
+		for i := 0; i < config.NumDevices; i++ {
+			config.Topology.Switches = append(config.Topology.Switches, fmt.Sprintf("s%d", i))
+		}
+	}
+	return config, nil
+}
 
+// createNetworkService creates a Service for each switch in the network
+func (c *ClusterController) createNetworkService(name string, config *NetworkConfig) error {
+	ports := config.Topology.getSwitchPorts()
+	for _, s := range config.Topology.Switches {
 		service := &corev1.Service{
 			ObjectMeta: metav1.ObjectMeta{
-				Name:      serviceName,
+				Name:      getSwitchDeviceName(name, s),
 				Namespace: c.clusterID,
-				Labels:    map[string]string{"network": name},
+				Labels: map[string]string{
+					"network": name,
+					"switch":  s,
+				},
 			},
 			Spec: corev1.ServiceSpec{
 				Selector: map[string]string{
@@ -245,8 +471,8 @@ func (c *ClusterController) createNetworkService(name string, config *NetworkCon
 				},
 				Ports: []corev1.ServicePort{
 					{
-						Name: serviceName,
-						Port: port,
+						Name: "stratum",
+						Port: int32(ports[s]),
 					},
 				},
 			},
@@ -255,9 +481,7 @@ func (c *ClusterController) createNetworkService(name string, config *NetworkCon
 		if err != nil {
 			return err
 		}
-		port = port + 1
 	}
-
 	return nil
 }
 
@@ -80,15 +80,44 @@ func (c *ClusterController) OpenShell(resourceID string) error {
 	return err
 }
 
-// setupOnosCli sets up the onos-cli deployment
-func (c *ClusterController) setupOnosCli() error {
-	if err := c.createCliDeployment(); err != nil {
-		return err
-	}
-	if err := c.awaitCliDeploymentReady(); err != nil {
-		return err
+// onosCliComponent is the onos-cli component
+type onosCliComponent struct{}
+
+// Name returns the name of the CLI component
+func (*onosCliComponent) Name() string {
+	return string(OnosCli)
+}
+
+// Description returns the description of the CLI component
+func (*onosCliComponent) Description() string {
+	return "CLI"
+}
+
+// ImageTags returns the default image tags for the CLI
+func (*onosCliComponent) ImageTags() map[string]string {
+	return map[string]string{
+		"cli": string(Latest),
 	}
-	return nil
+}
+
+// Setup sets up the onos-cli deployment
+func (*onosCliComponent) Setup(c *ClusterController) error {
+	return c.createCliDeployment()
+}
+
+// AwaitReady waits for the onos-cli Deployment to become ready
+func (*onosCliComponent) AwaitReady(c *ClusterController) error {
+	return c.awaitCliDeploymentReady()
+}
+
+// Teardown deletes the onos-cli resources
+func (*onosCliComponent) Teardown(c *ClusterController) error {
+	return c.deleteDeployments("onos-cli")
+}
+
+// Nodes returns the onos-cli nodes
+func (*onosCliComponent) Nodes(c *ClusterController) ([]NodeInfo, error) {
+	return c.GetOnosCliNodes()
 }
 
 // createCliDeployment creates an onos-cli deployment
@@ -148,7 +177,7 @@ func (c *ClusterController) awaitCliDeploymentReady() error {
 	}
 }
 
-// GetOnosTopoNodes returns a list of all onos-topo nodes running in the cluster
+// GetOnosCliNodes returns a list of all onos-cli nodes running in the cluster
 func (c *ClusterController) GetOnosCliNodes() ([]NodeInfo, error) {
 	topoLabelSelector := metav1.LabelSelector{MatchLabels: map[string]string{"app": "onos", "type": "cli"}}
 
@@ -161,17 +190,7 @@ func (c *ClusterController) GetOnosCliNodes() ([]NodeInfo, error) {
 
 	onosCliNodes := make([]NodeInfo, len(pods.Items))
 	for i, pod := range pods.Items {
-		var status NodeStatus
-		if pod.Status.Phase == corev1.PodRunning {
-			status = NodeRunning
-		} else if pod.Status.Phase == corev1.PodFailed {
-			status = NodeFailed
-		}
-		onosCliNodes[i] = NodeInfo{
-			ID:     pod.Name,
-			Status: status,
-			Type:   OnosCli,
-		}
+		onosCliNodes[i] = newNodeInfo(pod, OnosCli)
 	}
 
 	return onosCliNodes, nil
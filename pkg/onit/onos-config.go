@@ -30,8 +30,28 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
-// setupOnosConfig sets up the onos-config Deployment
-func (c *ClusterController) setupOnosConfig() error {
+// onosConfigComponent is the onos-config component
+type onosConfigComponent struct{}
+
+// Name returns the name of the onos-config component
+func (*onosConfigComponent) Name() string {
+	return string(OnosConfig)
+}
+
+// Description returns the description of the onos-config component
+func (*onosConfigComponent) Description() string {
+	return "onos-config cluster"
+}
+
+// ImageTags returns the default image tags for onos-config
+func (*onosConfigComponent) ImageTags() map[string]string {
+	return map[string]string{
+		"config": string(Debug),
+	}
+}
+
+// Setup sets up the onos-config Deployment
+func (*onosConfigComponent) Setup(c *ClusterController) error {
 	if err := c.createOnosConfigConfigMap(); err != nil {
 		return err
 	}
@@ -41,22 +61,28 @@ func (c *ClusterController) setupOnosConfig() error {
 	if err := c.createOnosConfigDeployment(); err != nil {
 		return err
 	}
-	if err := c.createOnosConfigProxyConfigMap(); err != nil {
-		return err
-	}
-	if err := c.createOnosConfigProxyDeployment(); err != nil {
-		return err
-	}
-	if err := c.createOnosConfigProxyService(); err != nil {
-		return err
-	}
-	if err := c.awaitOnosConfigDeploymentReady(); err != nil {
+	return nil
+}
+
+// AwaitReady waits for the onos-config Deployment to become ready
+func (*onosConfigComponent) AwaitReady(c *ClusterController) error {
+	return c.awaitOnosConfigDeploymentReady()
+}
+
+// Teardown deletes the onos-config resources
+func (*onosConfigComponent) Teardown(c *ClusterController) error {
+	if err := c.deleteDeployments("onos-config"); err != nil {
 		return err
 	}
-	if err := c.awaitOnosConfigProxyDeploymentReady(); err != nil {
+	if err := c.deleteServices("onos-config"); err != nil {
 		return err
 	}
-	return nil
+	return c.deleteConfigMaps("onos-config")
+}
+
+// Nodes returns the onos-config nodes
+func (*onosConfigComponent) Nodes(c *ClusterController) ([]NodeInfo, error) {
+	return c.GetOnosConfigNodes()
 }
 
 // createOnosConfigConfigMap creates a ConfigMap for the onos-config Deployment
@@ -106,44 +132,93 @@ func (c *ClusterController) createOnosConfigConfigMap() error {
 	return err
 }
 
-// createModelPluginString creates model plugin path based on a device type, version, and image tag
-func (c *ClusterController) createModelPluginString(deviceType string, version string, debug bool) string {
+// createModelPluginString creates the model plugin argument for the given plugin based on the image tag
+func (c *ClusterController) createModelPluginString(plugin ModelPlugin, debug bool) string {
 	var sb strings.Builder
-	sb.WriteString("-modelPlugin=/usr/local/lib/")
-	sb.WriteString(deviceType)
+	sb.WriteString("-modelPlugin=")
+	switch {
+	case plugin.Image != "":
+		sb.WriteString(modelPluginsPath)
+		sb.WriteString("/")
+		sb.WriteString(getModelPluginFile(plugin, debug))
+	case plugin.Path != "":
+		sb.WriteString(plugin.Path)
+	default:
+		sb.WriteString("/usr/local/lib/")
+		sb.WriteString(getModelPluginFile(plugin, debug))
+	}
+	return sb.String()
+}
+
+// getModelPluginFile returns the name of the shared object file for the given model plugin
+func getModelPluginFile(plugin ModelPlugin, debug bool) string {
+	var sb strings.Builder
+	sb.WriteString(plugin.Type)
 	if debug {
 		sb.WriteString("-debug.so.")
-		sb.WriteString(version)
+		sb.WriteString(plugin.Version)
 	} else {
 		sb.WriteString(".so.")
-		sb.WriteString(version)
+		sb.WriteString(plugin.Version)
 	}
-
 	return sb.String()
 }
 
+// getModelPlugins returns the model plugins to be loaded by onos-config
+func (c *ClusterController) getModelPlugins() []ModelPlugin {
+	if c.config.ModelPlugins == nil {
+		return GetDefaultModelPlugins()
+	}
+	return c.config.ModelPlugins
+}
+
+// newModelPluginInitContainers returns init containers that copy the model plugins provided by images
+// into the shared plugins volume
+func (c *ClusterController) newModelPluginInitContainers(debug bool) ([]corev1.Container, error) {
+	containers := []corev1.Container{}
+	for _, plugin := range c.getModelPlugins() {
+		if err := plugin.validate(); err != nil {
+			return nil, err
+		}
+		if plugin.Image == "" {
+			continue
+		}
+		containers = append(containers, corev1.Container{
+			Name:            fmt.Sprintf("%s-%s", plugin.Type, strings.Replace(plugin.Version, ".", "-", -1)),
+			Image:           plugin.Image,
+			ImagePullPolicy: c.config.PullPolicy,
+			Command:         []string{"cp", plugin.Path, fmt.Sprintf("%s/%s", modelPluginsPath, getModelPluginFile(plugin, debug))},
+			VolumeMounts: []corev1.VolumeMount{
+				{
+					Name:      "plugins",
+					MountPath: modelPluginsPath,
+				},
+			},
+		})
+	}
+	return containers, nil
+}
+
 // createOnosConfigDeployment creates an onos-config Deployment
 func (c *ClusterController) createOnosConfigDeployment() error {
 	nodes := int32(c.config.ConfigNodes)
 	zero := int64(0)
+	debug := c.config.ImageTags["config"] == string(Debug)
+	initContainers, err := c.newModelPluginInitContainers(debug)
+	if err != nil {
+		return err
+	}
 
-	testDevModelPluginV1 := ""
-	testDevModelPluginV2 := ""
-	testDevSimModelPluginV1 := ""
-	testStratumModelPluginV2 := ""
-
-	if c.config.ImageTags["config"] == string(Debug) {
-		testDevModelPluginV1 = c.createModelPluginString("testdevice", "1.0.0", true)
-		testDevModelPluginV2 = c.createModelPluginString("testdevice", "2.0.0", true)
-		testDevSimModelPluginV1 = c.createModelPluginString("devicesim", "1.0.0", true)
-		testStratumModelPluginV2 = c.createModelPluginString("stratum", "1.0.0", true)
-
-	} else {
-		testDevModelPluginV1 = c.createModelPluginString("testdevice", "1.0.0", false)
-		testDevModelPluginV2 = c.createModelPluginString("testdevice", "2.0.0", false)
-		testDevSimModelPluginV1 = c.createModelPluginString("devicesim", "1.0.0", false)
-		testStratumModelPluginV2 = c.createModelPluginString("stratum", "1.0.0", false)
-
+	args := []string{
+		"-caPath=/etc/onos-config/certs/onf.cacrt",
+		"-keyPath=/etc/onos-config/certs/onos-config.key",
+		"-certPath=/etc/onos-config/certs/onos-config.crt",
+		"-configStore=/etc/onos-config/configs/configStore.json",
+		"-changeStore=/etc/onos-config/configs/changeStore.json",
+		"-networkStore=/etc/onos-config/configs/networkStore.json",
+	}
+	for _, plugin := range c.getModelPlugins() {
+		args = append(args, c.createModelPluginString(plugin, debug))
 	}
 
 	dep := &appsv1.Deployment{
@@ -171,6 +246,7 @@ func (c *ClusterController) createOnosConfigDeployment() error {
 					},
 				},
 				Spec: corev1.PodSpec{
+					InitContainers: initContainers,
 					Containers: []corev1.Container{
 						{
 							Name:            "onos-config",
@@ -194,18 +270,7 @@ func (c *ClusterController) createOnosConfigDeployment() error {
 									Value: "raft",
 								},
 							},
-							Args: []string{
-								"-caPath=/etc/onos-config/certs/onf.cacrt",
-								"-keyPath=/etc/onos-config/certs/onos-config.key",
-								"-certPath=/etc/onos-config/certs/onos-config.crt",
-								"-configStore=/etc/onos-config/configs/configStore.json",
-								"-changeStore=/etc/onos-config/configs/changeStore.json",
-								"-networkStore=/etc/onos-config/configs/networkStore.json",
-								testDevModelPluginV1,
-								testDevModelPluginV2,
-								testDevSimModelPluginV1,
-								testStratumModelPluginV2,
-							},
+							Args: args,
 							Ports: []corev1.ContainerPort{
 								{
 									Name:          "grpc",
@@ -246,6 +311,11 @@ func (c *ClusterController) createOnosConfigDeployment() error {
 									MountPath: "/etc/onos-config/certs",
 									ReadOnly:  true,
 								},
+								{
+									Name:      "plugins",
+									MountPath: modelPluginsPath,
+									ReadOnly:  true,
+								},
 							},
 							SecurityContext: &corev1.SecurityContext{
 								Capabilities: &corev1.Capabilities{
@@ -278,12 +348,18 @@ func (c *ClusterController) createOnosConfigDeployment() error {
 								},
 							},
 						},
+						{
+							Name: "plugins",
+							VolumeSource: corev1.VolumeSource{
+								EmptyDir: &corev1.EmptyDirVolumeSource{},
+							},
+						},
 					},
 				},
 			},
 		},
 	}
-	_, err := c.kubeclient.AppsV1().Deployments(c.clusterID).Create(dep)
+	_, err = c.kubeclient.AppsV1().Deployments(c.clusterID).Create(dep)
 	return err
 }
 
@@ -510,17 +586,7 @@ func (c *ClusterController) GetOnosConfigNodes() ([]NodeInfo, error) {
 
 	onosConfigNodes := make([]NodeInfo, len(pods.Items))
 	for i, pod := range pods.Items {
-		var status NodeStatus
-		if pod.Status.Phase == corev1.PodRunning {
-			status = NodeRunning
-		} else if pod.Status.Phase == corev1.PodFailed {
-			status = NodeFailed
-		}
-		onosConfigNodes[i] = NodeInfo{
-			ID:     pod.Name,
-			Status: status,
-			Type:   OnosConfig,
-		}
+		onosConfigNodes[i] = newNodeInfo(pod, OnosConfig)
 	}
 
 	return onosConfigNodes, nil
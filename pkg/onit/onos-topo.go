@@ -15,15 +15,11 @@
 package onit
 
 import (
-	"bytes"
 	"fmt"
 	"io/ioutil"
 	"path/filepath"
-	"strconv"
 	"time"
 
-	"gopkg.in/yaml.v1"
-
 	"k8s.io/apimachinery/pkg/labels"
 
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -33,8 +29,28 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-// setupOnosTopo sets up the onos-topo Deployment
-func (c *ClusterController) setupOnosTopo() error {
+// onosTopoComponent is the onos-topo component
+type onosTopoComponent struct{}
+
+// Name returns the name of the onos-topo component
+func (*onosTopoComponent) Name() string {
+	return string(OnosTopo)
+}
+
+// Description returns the description of the onos-topo component
+func (*onosTopoComponent) Description() string {
+	return "onos-topo cluster"
+}
+
+// ImageTags returns the default image tags for onos-topo
+func (*onosTopoComponent) ImageTags() map[string]string {
+	return map[string]string{
+		"topo": string(Debug),
+	}
+}
+
+// Setup sets up the onos-topo Deployment
+func (*onosTopoComponent) Setup(c *ClusterController) error {
 	if err := c.createOnosTopoConfigMap(); err != nil {
 		return err
 	}
@@ -44,22 +60,28 @@ func (c *ClusterController) setupOnosTopo() error {
 	if err := c.createOnosTopoDeployment(); err != nil {
 		return err
 	}
-	if err := c.createOnosTopoProxyConfigMap(); err != nil {
-		return err
-	}
-	if err := c.createOnosTopoProxyDeployment(); err != nil {
-		return err
-	}
-	if err := c.createOnosTopoProxyService(); err != nil {
-		return err
-	}
-	if err := c.awaitOnosTopoDeploymentReady(); err != nil {
+	return nil
+}
+
+// AwaitReady waits for the onos-topo Deployment to become ready
+func (*onosTopoComponent) AwaitReady(c *ClusterController) error {
+	return c.awaitOnosTopoDeploymentReady()
+}
+
+// Teardown deletes the onos-topo resources
+func (*onosTopoComponent) Teardown(c *ClusterController) error {
+	if err := c.deleteDeployments("onos-topo"); err != nil {
 		return err
 	}
-	if err := c.awaitOnosTopoProxyDeploymentReady(); err != nil {
+	if err := c.deleteServices("onos-topo"); err != nil {
 		return err
 	}
-	return nil
+	return c.deleteConfigMaps("onos-topo")
+}
+
+// Nodes returns the onos-topo nodes
+func (*onosTopoComponent) Nodes(c *ClusterController) ([]NodeInfo, error) {
+	return c.GetOnosTopoNodes()
 }
 
 // createOnosTopoConfigMap creates a ConfigMap for the onos-topo Deployment
@@ -422,65 +444,36 @@ func (c *ClusterController) awaitOnosTopoProxyDeploymentReady() error {
 }
 
 // addSimulatorToTopo adds a simulator to onos-topo
-func (c *ClusterController) addSimulatorToTopo(name string) error {
-	return c.addDevice("Devicesim", name, 11161)
+func (c *ClusterController) addSimulatorToTopo(name string, config *SimulatorConfig) error {
+	return c.addDevices(newSimulatorDevice(name, config))
 }
 
 // addNetworkToTopo adds a network to onos-topo
 func (c *ClusterController) addNetworkToTopo(name string, config *NetworkConfig) error {
-	var port = 50001
-	for i := 0; i < config.NumDevices; i++ {
-		var buf bytes.Buffer
-		buf.WriteString(name)
-		buf.WriteString("-s")
-		buf.WriteString(strconv.Itoa(i))
-		deviceName := buf.String()
-		if err := c.addDevice("Stratum", deviceName, port); err != nil {
-			return err
-		}
-		port = port + 1
-	}
-	return nil
-}
-
-// addDevice adds the given device via the CLI
-func (c *ClusterController) addDevice(deviceType string, name string, port int) error {
-	command := fmt.Sprintf("onos topo add device %s --type %s --address %s:%d --version 1.0.0 --plain --timeout 15s", name, deviceType, name, port)
-	return c.executeCLI(command)
+	return c.addDevices(getNetworkDevices(name, config)...)
 }
 
 // removeSimulatorFromConfig removes a simulator from the onos-config configuration
 func (c *ClusterController) removeSimulatorFromConfig(name string) error {
-	return c.removeDevice(name)
+	return c.removeDevices(name)
 }
 
 // removeNetworkFromConfig removes a network from the onos-config configuration
 func (c *ClusterController) removeNetworkFromConfig(name string, configMap *corev1.ConfigMapList) error {
-	dataMap := configMap.Items[0].BinaryData["config"]
-	m := make(map[string]interface{})
-	err := yaml.Unmarshal(dataMap, &m)
+	if len(configMap.Items) == 0 {
+		return fmt.Errorf("configuration for network %s not found", name)
+	}
+	config, err := decodeNetworkConfig(configMap.Items[0].BinaryData["config"])
 	if err != nil {
 		return err
 	}
-	numDevices := m["numdevices"].(int)
-
-	for i := 0; i < numDevices; i++ {
-		var buf bytes.Buffer
-		buf.WriteString(name)
-		buf.WriteString("-s")
-		buf.WriteString(strconv.Itoa(i))
-		deviceName := buf.String()
-		if err = c.removeDevice(deviceName); err != nil {
-			return err
-		}
-	}
-	return nil
-}
 
-// removeDevice removes the given device via the given pod
-func (c *ClusterController) removeDevice(name string) error {
-	command := fmt.Sprintf("onos topo remove device %s", name)
-	return c.executeCLI(command)
+	devices := getNetworkDevices(name, config)
+	deviceNames := make([]string, len(devices))
+	for i, device := range devices {
+		deviceNames[i] = device.ID
+	}
+	return c.removeDevices(deviceNames...)
 }
 
 // GetOnosTopoNodes returns a list of all onos-topo nodes running in the cluster
@@ -496,17 +489,7 @@ func (c *ClusterController) GetOnosTopoNodes() ([]NodeInfo, error) {
 
 	onosTopoNodes := make([]NodeInfo, len(pods.Items))
 	for i, pod := range pods.Items {
-		var status NodeStatus
-		if pod.Status.Phase == corev1.PodRunning {
-			status = NodeRunning
-		} else if pod.Status.Phase == corev1.PodFailed {
-			status = NodeFailed
-		}
-		onosTopoNodes[i] = NodeInfo{
-			ID:     pod.Name,
-			Status: status,
-			Type:   OnosTopo,
-		}
+		onosTopoNodes[i] = newNodeInfo(pod, OnosTopo)
 	}
 
 	return onosTopoNodes, nil
@@ -22,7 +22,6 @@ import (
 	"github.com/atomix/atomix-k8s-controller/pkg/apis/k8s/v1alpha1"
 	raft "github.com/atomix/atomix-k8s-controller/proto/atomix/protocols/raft"
 	"github.com/ghodss/yaml"
-	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -31,6 +30,7 @@ type PartitionInfo struct {
 	Group     string
 	Partition int
 	Nodes     []string
+	Ready     int
 }
 
 // GetPartitions returns a list of partition info
@@ -49,22 +49,34 @@ func (c *ClusterController) GetPartitions() ([]PartitionInfo, error) {
 			return nil, err
 		}
 
-		nodes, err := c.GetPartitionNodes(int(partitionID))
+		info, err := c.getPartition(int(partitionID))
 		if err != nil {
 			return nil, err
 		}
-		nodeIds := make([]string, len(nodes))
-		for j, node := range nodes {
-			nodeIds[j] = node.ID
-		}
+		partitions[i] = info
+	}
+	return partitions, nil
+}
 
-		partitions[i] = PartitionInfo{
-			Group:     "raft",
-			Partition: int(partitionID),
-			Nodes:     nodeIds,
+// getPartition returns the partition info for the given partition
+func (c *ClusterController) getPartition(partition int) (PartitionInfo, error) {
+	nodes, err := c.GetPartitionNodes(partition)
+	if err != nil {
+		return PartitionInfo{}, err
+	}
+
+	info := PartitionInfo{
+		Group:     "raft",
+		Partition: partition,
+		Nodes:     make([]string, len(nodes)),
+	}
+	for i, node := range nodes {
+		info.Nodes[i] = node.ID
+		if node.Ready {
+			info.Ready++
 		}
 	}
-	return partitions, nil
+	return info, nil
 }
 
 // GetPartitionNodes returns a list of node info for the given partition
@@ -78,31 +90,11 @@ func (c *ClusterController) GetPartitionNodes(partition int) ([]NodeInfo, error)
 
 	nodes := make([]NodeInfo, len(pods.Items))
 	for i, pod := range pods.Items {
-		var status NodeStatus
-		if pod.Status.Phase == corev1.PodRunning {
-			status = NodeRunning
-		} else if pod.Status.Phase == corev1.PodFailed {
-			status = NodeFailed
-		}
-		nodes[i] = NodeInfo{
-			ID:     pod.Name,
-			Status: status,
-		}
+		nodes[i] = newNodeInfo(pod, "")
 	}
 	return nodes, nil
 }
 
-// setupPartitions creates a Raft partition set
-func (c *ClusterController) setupPartitions() error {
-	if err := c.createPartitionSet(); err != nil {
-		return err
-	}
-	if err := c.awaitPartitionsReady(); err != nil {
-		return err
-	}
-	return nil
-}
-
 // createPartitionSet creates a Raft partition set from the configuration
 func (c *ClusterController) createPartitionSet() error {
 	bytes, err := yaml.Marshal(&raft.RaftProtocol{})
@@ -0,0 +1,202 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package pki generates the certificate authorities and certificates used to secure onit clusters
+package pki
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net"
+	"time"
+)
+
+const (
+	// keySize is the size of generated RSA keys
+	keySize = 2048
+
+	// caValidity is the validity period of generated certificate authorities
+	caValidity = 10 * 365 * 24 * time.Hour
+
+	// certValidity is the default validity period of issued certificates
+	certValidity = 365 * 24 * time.Hour
+
+	// organization is the organization of generated certificates
+	organization = "Open Networking Foundation"
+)
+
+// Certificate is a PEM encoded certificate and its private key
+type Certificate struct {
+	// Cert is the PEM encoded certificate
+	Cert []byte
+
+	// Key is the PEM encoded private key
+	Key []byte
+
+	cert *x509.Certificate
+	key  *rsa.PrivateKey
+}
+
+// Request is a request for a certificate to be issued by a certificate authority
+type Request struct {
+	// CommonName is the common name of the certificate
+	CommonName string
+
+	// DNSNames is the list of DNS subject alternative names
+	DNSNames []string
+
+	// IPAddresses is the list of IP subject alternative names
+	IPAddresses []net.IP
+
+	// Server indicates whether the certificate can be used to authenticate servers
+	Server bool
+
+	// Client indicates whether the certificate can be used to authenticate clients
+	Client bool
+
+	// NotBefore is the time from which the certificate is valid, defaulting to the current time
+	NotBefore time.Time
+
+	// NotAfter is the time until which the certificate is valid, defaulting to one year after NotBefore
+	NotAfter time.Time
+}
+
+// NewCA generates a new self-signed certificate authority with the given common name
+func NewCA(commonName string) (*Certificate, error) {
+	key, err := rsa.GenerateKey(rand.Reader, keySize)
+	if err != nil {
+		return nil, err
+	}
+
+	serial, err := newSerialNumber()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject: pkix.Name{
+			CommonName:   commonName,
+			Organization: []string{organization},
+		},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(caValidity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	return newCertificate(template, template, key, key)
+}
+
+// Load loads a certificate and private key from the given PEM encoded bytes
+func Load(certPEM []byte, keyPEM []byte) (*Certificate, error) {
+	pair, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		return nil, err
+	}
+	key, ok := pair.PrivateKey.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("unsupported private key type")
+	}
+	return &Certificate{
+		Cert: certPEM,
+		Key:  keyPEM,
+		cert: cert,
+		key:  key,
+	}, nil
+}
+
+// Issue issues a new certificate signed by the certificate authority
+func (c *Certificate) Issue(request Request) (*Certificate, error) {
+	if !c.cert.IsCA {
+		return nil, errors.New("certificate is not a certificate authority")
+	}
+
+	key, err := rsa.GenerateKey(rand.Reader, keySize)
+	if err != nil {
+		return nil, err
+	}
+
+	serial, err := newSerialNumber()
+	if err != nil {
+		return nil, err
+	}
+
+	notBefore := request.NotBefore
+	if notBefore.IsZero() {
+		notBefore = time.Now().Add(-time.Hour)
+	}
+	notAfter := request.NotAfter
+	if notAfter.IsZero() {
+		notAfter = notBefore.Add(certValidity)
+	}
+
+	usages := []x509.ExtKeyUsage{}
+	if request.Server {
+		usages = append(usages, x509.ExtKeyUsageServerAuth)
+	}
+	if request.Client {
+		usages = append(usages, x509.ExtKeyUsageClientAuth)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject: pkix.Name{
+			CommonName:   request.CommonName,
+			Organization: []string{organization},
+		},
+		DNSNames:              request.DNSNames,
+		IPAddresses:           request.IPAddresses,
+		NotBefore:             notBefore,
+		NotAfter:              notAfter,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:           usages,
+		BasicConstraintsValid: true,
+	}
+	return newCertificate(template, c.cert, key, c.key)
+}
+
+// newCertificate creates a certificate from the given template signed by the given parent
+func newCertificate(template *x509.Certificate, parent *x509.Certificate, key *rsa.PrivateKey, parentKey *rsa.PrivateKey) (*Certificate, error) {
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+	return &Certificate{
+		Cert: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		Key:  pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}),
+		cert: cert,
+		key:  key,
+	}, nil
+}
+
+// newSerialNumber returns a random certificate serial number
+func newSerialNumber() (*big.Int, error) {
+	return rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+}
@@ -0,0 +1,160 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pki
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func parseCert(t *testing.T, cert *Certificate) *x509.Certificate {
+	block, _ := pem.Decode(cert.Cert)
+	assert.NotNil(t, block)
+	parsed, err := x509.ParseCertificate(block.Bytes)
+	assert.NoError(t, err)
+	return parsed
+}
+
+func TestNewCA(t *testing.T) {
+	ca, err := NewCA("test-ca")
+	assert.NoError(t, err)
+
+	cert := parseCert(t, ca)
+	assert.True(t, cert.IsCA)
+	assert.Equal(t, "test-ca", cert.Subject.CommonName)
+	assert.Equal(t, []string{organization}, cert.Subject.Organization)
+	assert.NoError(t, cert.CheckSignatureFrom(cert))
+}
+
+func TestLoad(t *testing.T) {
+	ca, err := NewCA("test-ca")
+	assert.NoError(t, err)
+
+	loaded, err := Load(ca.Cert, ca.Key)
+	assert.NoError(t, err)
+	assert.Equal(t, ca.Cert, loaded.Cert)
+	assert.Equal(t, ca.Key, loaded.Key)
+
+	// A loaded CA can issue certificates verified by the original
+	issued, err := loaded.Issue(Request{CommonName: "client", Client: true})
+	assert.NoError(t, err)
+	assert.NoError(t, parseCert(t, issued).CheckSignatureFrom(parseCert(t, ca)))
+
+	other, err := NewCA("other-ca")
+	assert.NoError(t, err)
+	_, err = Load(ca.Cert, other.Key)
+	assert.Error(t, err)
+
+	_, err = Load([]byte("invalid"), ca.Key)
+	assert.Error(t, err)
+}
+
+func TestIssue(t *testing.T) {
+	ca, err := NewCA("test-ca")
+	assert.NoError(t, err)
+	caCert := parseCert(t, ca)
+
+	notBefore := time.Now().Add(-48 * time.Hour).Truncate(time.Second)
+	notAfter := time.Now().Add(-24 * time.Hour).Truncate(time.Second)
+
+	tests := []struct {
+		name    string
+		request Request
+		usages  []x509.ExtKeyUsage
+		expired bool
+	}{
+		{
+			name: "server",
+			request: Request{
+				CommonName:  "onos-config",
+				DNSNames:    []string{"onos-config", "onos-config.onit-1.svc"},
+				IPAddresses: []net.IP{net.ParseIP("127.0.0.1")},
+				Server:      true,
+			},
+			usages: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		},
+		{
+			name: "client",
+			request: Request{
+				CommonName: "client1",
+				Client:     true,
+			},
+			usages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		},
+		{
+			name: "server and client",
+			request: Request{
+				CommonName: "onos-config",
+				Server:     true,
+				Client:     true,
+			},
+			usages: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		},
+		{
+			name: "expired",
+			request: Request{
+				CommonName: "client1",
+				Client:     true,
+				NotBefore:  notBefore,
+				NotAfter:   notAfter,
+			},
+			usages:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+			expired: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			issued, err := ca.Issue(test.request)
+			assert.NoError(t, err)
+
+			cert := parseCert(t, issued)
+			assert.False(t, cert.IsCA)
+			assert.Equal(t, test.request.CommonName, cert.Subject.CommonName)
+			assert.Equal(t, test.request.DNSNames, cert.DNSNames)
+			assert.Equal(t, len(test.request.IPAddresses), len(cert.IPAddresses))
+			assert.Equal(t, test.usages, cert.ExtKeyUsage)
+			assert.NoError(t, cert.CheckSignatureFrom(caCert))
+
+			if test.expired {
+				assert.True(t, cert.NotBefore.Equal(notBefore))
+				assert.True(t, cert.NotAfter.Equal(notAfter))
+			} else {
+				assert.True(t, cert.NotBefore.Before(time.Now()))
+				assert.Equal(t, certValidity, cert.NotAfter.Sub(cert.NotBefore))
+			}
+
+			// The issued certificate and key must form a valid key pair
+			_, err = Load(issued.Cert, issued.Key)
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestIssueRequiresCA(t *testing.T) {
+	ca, err := NewCA("test-ca")
+	assert.NoError(t, err)
+
+	issued, err := ca.Issue(Request{CommonName: "client1", Client: true})
+	assert.NoError(t, err)
+
+	_, err = issued.Issue(Request{CommonName: "client2", Client: true})
+	assert.Error(t, err)
+}
@@ -0,0 +1,92 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package onit
+
+// proxyComponent is the Envoy proxy component, which exposes the onos-config and onos-topo gRPC services
+// to gRPC-web clients like the GUI
+type proxyComponent struct{}
+
+// Name returns the name of the proxy component
+func (*proxyComponent) Name() string {
+	return "proxy"
+}
+
+// Description returns the description of the proxy component
+func (*proxyComponent) Description() string {
+	return "Envoy proxies"
+}
+
+// ImageTags returns no image tags. The proxies always use the latest Envoy image.
+func (*proxyComponent) ImageTags() map[string]string {
+	return map[string]string{}
+}
+
+// Setup sets up a proxy for each of onos-topo and onos-config enabled in the cluster
+func (*proxyComponent) Setup(c *ClusterController) error {
+	if c.config.IsEnabled(string(OnosTopo)) {
+		if err := c.createOnosTopoProxyConfigMap(); err != nil {
+			return err
+		}
+		if err := c.createOnosTopoProxyDeployment(); err != nil {
+			return err
+		}
+		if err := c.createOnosTopoProxyService(); err != nil {
+			return err
+		}
+	}
+	if c.config.IsEnabled(string(OnosConfig)) {
+		if err := c.createOnosConfigProxyConfigMap(); err != nil {
+			return err
+		}
+		if err := c.createOnosConfigProxyDeployment(); err != nil {
+			return err
+		}
+		if err := c.createOnosConfigProxyService(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AwaitReady waits for the proxy Deployments to become ready
+func (*proxyComponent) AwaitReady(c *ClusterController) error {
+	if c.config.IsEnabled(string(OnosTopo)) {
+		if err := c.awaitOnosTopoProxyDeploymentReady(); err != nil {
+			return err
+		}
+	}
+	if c.config.IsEnabled(string(OnosConfig)) {
+		if err := c.awaitOnosConfigProxyDeploymentReady(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Teardown deletes the proxy resources
+func (*proxyComponent) Teardown(c *ClusterController) error {
+	if err := c.deleteDeployments("onos-topo-envoy", "onos-config-envoy"); err != nil {
+		return err
+	}
+	if err := c.deleteServices("onos-topo-envoy", "onos-config-envoy"); err != nil {
+		return err
+	}
+	return c.deleteConfigMaps("onos-topo-envoy", "onos-config-envoy")
+}
+
+// Nodes returns no nodes. Proxies are not onos nodes.
+func (*proxyComponent) Nodes(c *ClusterController) ([]NodeInfo, error) {
+	return []NodeInfo{}, nil
+}
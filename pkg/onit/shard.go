@@ -0,0 +1,379 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package onit
+
+import (
+	"bufio"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/onosproject/onos-test/pkg/onit/console"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// shardLabel is the label applied to the jobs of a sharded test run, identifying the test run
+	shardLabel = "test-shard-of"
+
+	// defaultTestDuration is the estimated duration of tests with no recorded history
+	defaultTestDuration = time.Second
+)
+
+// testShard is a single job of a sharded test run
+type testShard struct {
+	testID string
+	tests  []string
+	pod    corev1.Pod
+}
+
+// RunShardedTests runs the tests of the given suite split across the given number of jobs. The tests are
+// balanced across shards using the durations recorded in the test history.
+func (c *ClusterController) RunShardedTests(testID string, suite string, tests []string, shards int, devices int, timeout time.Duration) (TestRecord, console.ErrorStatus) {
+	// Default the test timeout to 10 minutes
+	if timeout == 0 {
+		timeout = 10 * time.Minute
+	}
+
+	c.status.Start(fmt.Sprintf("Splitting tests across %d shards", shards))
+	durations, err := c.getTestDurations()
+	if err != nil {
+		return TestRecord{}, c.status.Fail(err)
+	}
+	groups := splitTests(tests, shards, durations)
+
+	// If all the devices are requested, divide the cluster's devices between the shards
+	if devices == AllDevices {
+		deviceIds, err := c.getDeviceIds()
+		if err != nil {
+			return TestRecord{}, c.status.Fail(err)
+		}
+		devices = len(deviceIds) / len(groups)
+		if devices == 0 && len(deviceIds) > 0 {
+			return TestRecord{}, c.status.Fail(fmt.Errorf("cannot divide %d devices between %d shards", len(deviceIds), len(groups)))
+		}
+	}
+
+	// Cancel the shards if the user interrupts the run before their logs have been streamed
+	interrupted, stopInterrupt := notifyInterrupt()
+	defer stopInterrupt()
+
+	// Start a test job for each shard
+	args := []string{string(TestTypeSuite), suite}
+	testShards := make([]*testShard, 0, len(groups))
+	defer func() {
+		for _, shard := range testShards {
+			c.releaseDevices(shard.testID)
+		}
+	}()
+	for i, group := range groups {
+		shard := &testShard{
+			testID: fmt.Sprintf("%s-%d", testID, i+1),
+			tests:  group,
+		}
+
+		c.status.Start("Reserving devices for shard " + shard.testID)
+		deviceIds, err := c.acquireDevices(shard.testID, devices, timeout, interrupted)
+		if err == errInterrupted {
+			return c.cancelShards(testID, testShards)
+		} else if err != nil {
+			return TestRecord{}, c.status.Fail(err)
+		}
+		testShards = append(testShards, shard)
+
+		c.status.Start("Starting test job: " + shard.testID)
+		if err := c.createShardJob(testID, shard, args, deviceIds, devices, timeout); err != nil {
+			return TestRecord{}, c.status.Fail(err)
+		}
+		pod, err := c.awaitTestJobRunning(shard.testID, interrupted)
+		if err == errInterrupted {
+			return c.cancelShards(testID, testShards)
+		} else if err != nil {
+			return TestRecord{}, c.status.Fail(err)
+		}
+		shard.pod = pod
+	}
+	c.status.Succeed()
+
+	// Stream the interleaved logs of all the shards to stdout, stopping if the user interrupts the run
+	mu := &sync.Mutex{}
+	wg := &sync.WaitGroup{}
+	streamed := make(chan struct{})
+	for i, shard := range testShards {
+		wg.Add(1)
+		go func(prefix string, shard *testShard) {
+			defer wg.Done()
+			reader, err := c.streamLogs(shard.pod)
+			if err != nil {
+				return
+			}
+			defer reader.Close()
+
+			go func() {
+				select {
+				case <-interrupted:
+					reader.Close()
+				case <-streamed:
+				}
+			}()
+
+			scanner := bufio.NewScanner(reader)
+			for scanner.Scan() {
+				mu.Lock()
+				fmt.Printf("[%s] %s\n", prefix, scanner.Text())
+				mu.Unlock()
+			}
+		}(fmt.Sprintf("shard-%d", i+1), shard)
+	}
+	wg.Wait()
+	close(streamed)
+
+	// If the run was interrupted, cancel the shards. Otherwise all the shards have finished, so later
+	// interrupts must not cancel their jobs.
+	select {
+	case <-interrupted:
+		return c.cancelShards(testID, testShards)
+	default:
+		stopInterrupt()
+	}
+
+	for _, shard := range testShards {
+		_, status, err := c.getStatus(shard.pod)
+		if err != nil {
+			c.status.Start("Loading shard status: " + shard.testID)
+			return TestRecord{}, c.status.Fail(err)
+		}
+		if err := c.recordTestResults(shard.testID, shard.pod); err != nil {
+			c.status.Start("Recording test results for shard " + shard.testID)
+			c.status.Fail(err)
+		}
+
+		// If the shard failed, capture the logs of the cluster's nodes before they're lost
+		if status != 0 {
+			c.status.Start("Capturing test artifacts for shard " + shard.testID)
+			if err := c.captureArtifacts(shard.testID); err != nil {
+				c.status.Fail(err)
+			} else {
+				c.status.Succeed()
+			}
+		}
+	}
+
+	record, err := c.GetRecord(testID)
+	if err != nil {
+		c.status.Start("Loading test record")
+		return TestRecord{}, c.status.Fail(err)
+	}
+	return record, c.status
+}
+
+// cancelShards cancels the given shards of an interrupted test run and returns the record of the test run
+func (c *ClusterController) cancelShards(testID string, shards []*testShard) (TestRecord, console.ErrorStatus) {
+	if len(shards) == 0 {
+		return TestRecord{}, c.status.Fail(errInterrupted)
+	}
+	for _, shard := range shards {
+		c.CancelTests(shard.testID)
+	}
+	record, err := c.GetRecord(testID)
+	if err != nil {
+		c.status.Start("Loading test record")
+		return TestRecord{}, c.status.Fail(err)
+	}
+	return record, c.status
+}
+
+// createShardJob creates the job for the given shard of a sharded test run
+func (c *ClusterController) createShardJob(testID string, shard *testShard, args []string, devices []string, deviceCount int, timeout time.Duration) error {
+	job, err := c.newTestJob(shard.testID, append([]string{string(TestTypeTest)}, shard.tests...), devices, deviceCount, timeout)
+	if err != nil {
+		return err
+	}
+	if job.Labels == nil {
+		job.Labels = make(map[string]string)
+	}
+	job.Labels[shardLabel] = testID
+	job.Annotations["test-shard-args"] = strings.Join(args, ",")
+	_, err = c.kubeclient.BatchV1().Jobs(c.clusterID).Create(job)
+	return err
+}
+
+// getShardJobs returns the jobs for the shards of the given test run
+func (c *ClusterController) getShardJobs(testID string) ([]batchv1.Job, error) {
+	jobs, err := c.kubeclient.BatchV1().Jobs(c.clusterID).List(metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", shardLabel, testID),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return jobs.Items, nil
+}
+
+// getShardedRecord merges the records of the given shard jobs into a single record for the test run
+func (c *ClusterController) getShardedRecord(testID string, jobs []batchv1.Job) (TestRecord, error) {
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].CreationTimestamp.Before(&jobs[j].CreationTimestamp)
+	})
+
+	records := make([]TestRecord, 0, len(jobs))
+	for _, job := range jobs {
+		record, err := c.getRecord(job)
+		if err != nil {
+			return TestRecord{}, err
+		}
+		records = append(records, record)
+	}
+
+	args := strings.Split(jobs[0].Annotations["test-shard-args"], ",")
+	return mergeShardRecords(testID, args, records), nil
+}
+
+// mergeShardRecords merges the records of the shards of a test run into a single record
+func mergeShardRecords(testID string, args []string, records []TestRecord) TestRecord {
+	merged := TestRecord{
+		TestID:  testID,
+		Type:    TestType(args[0]),
+		Args:    args,
+		Status:  TestPassed,
+		Results: make([]TestResult, 0),
+	}
+
+	failed := 0
+	for _, record := range records {
+		switch record.Status {
+		case TestRunning:
+			merged.Status = TestRunning
+		case TestCancelled:
+			if merged.Status != TestRunning {
+				merged.Status = TestCancelled
+			}
+		case TestFailed:
+			failed++
+			if merged.Status == TestPassed {
+				merged.Status = TestFailed
+			}
+		}
+
+		if record.ExitCode > merged.ExitCode {
+			merged.ExitCode = record.ExitCode
+		}
+		if !record.StartTime.IsZero() && (merged.StartTime.IsZero() || record.StartTime.Before(merged.StartTime)) {
+			merged.StartTime = record.StartTime
+		}
+		if record.EndTime.After(merged.EndTime) {
+			merged.EndTime = record.EndTime
+		}
+		merged.Results = append(merged.Results, record.Results...)
+		merged.Devices = append(merged.Devices, record.Devices...)
+		if merged.Images == nil {
+			merged.Images = record.Images
+		}
+		if merged.Config == nil {
+			merged.Config = record.Config
+		}
+	}
+
+	switch merged.Status {
+	case TestRunning:
+		if !merged.StartTime.IsZero() {
+			merged.Duration = time.Since(merged.StartTime)
+		}
+	case TestCancelled:
+		merged.Message = "test run cancelled"
+		merged.Duration = merged.EndTime.Sub(merged.StartTime)
+	case TestFailed:
+		merged.Message = fmt.Sprintf("%d of %d shards failed", failed, len(records))
+		merged.Duration = merged.EndTime.Sub(merged.StartTime)
+	default:
+		merged.Duration = merged.EndTime.Sub(merged.StartTime)
+	}
+	return merged
+}
+
+// getTestDurations returns the most recently recorded duration of each test in the test history
+func (c *ClusterController) getTestDurations() (map[string]time.Duration, error) {
+	records, err := c.GetHistory()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].StartTime.Before(records[j].StartTime)
+	})
+
+	durations := make(map[string]time.Duration)
+	for _, record := range records {
+		for _, result := range record.Results {
+			if result.Status == TestPassed || result.Status == TestFailed {
+				durations[result.Name] = result.Duration
+			}
+		}
+	}
+	return durations, nil
+}
+
+// splitTests splits the given tests into at most n groups with balanced total durations
+func splitTests(tests []string, n int, durations map[string]time.Duration) [][]string {
+	if n > len(tests) {
+		n = len(tests)
+	}
+	if n < 1 {
+		n = 1
+	}
+
+	// Estimate the duration of tests without history as the average of the known durations
+	estimate := defaultTestDuration
+	var total time.Duration
+	known := 0
+	for _, test := range tests {
+		if duration, ok := durations[test]; ok {
+			total += duration
+			known++
+		}
+	}
+	if known > 0 {
+		estimate = total / time.Duration(known)
+	}
+	duration := func(test string) time.Duration {
+		if d, ok := durations[test]; ok {
+			return d
+		}
+		return estimate
+	}
+
+	// Assign the longest tests first, each to the shard with the shortest total duration
+	sorted := make([]string, len(tests))
+	copy(sorted, tests)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return duration(sorted[i]) > duration(sorted[j])
+	})
+
+	groups := make([][]string, n)
+	totals := make([]time.Duration, n)
+	for _, test := range sorted {
+		shortest := 0
+		for i := range totals {
+			if totals[i] < totals[shortest] {
+				shortest = i
+			}
+		}
+		groups[shortest] = append(groups[shortest], test)
+		totals[shortest] += duration(test)
+	}
+	return groups
+}
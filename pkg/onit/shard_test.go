@@ -0,0 +1,181 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package onit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitTests(t *testing.T) {
+	tests := []struct {
+		name      string
+		tests     []string
+		shards    int
+		durations map[string]time.Duration
+		expected  [][]string
+	}{
+		{
+			name:     "single shard",
+			tests:    []string{"a", "b", "c"},
+			shards:   1,
+			expected: [][]string{{"a", "b", "c"}},
+		},
+		{
+			name:     "no history",
+			tests:    []string{"a", "b", "c", "d"},
+			shards:   2,
+			expected: [][]string{{"a", "c"}, {"b", "d"}},
+		},
+		{
+			name:     "more shards than tests",
+			tests:    []string{"a", "b"},
+			shards:   5,
+			expected: [][]string{{"a"}, {"b"}},
+		},
+		{
+			name:     "no shards",
+			tests:    []string{"a", "b"},
+			shards:   0,
+			expected: [][]string{{"a", "b"}},
+		},
+		{
+			name:   "balanced by duration",
+			tests:  []string{"a", "b", "c", "d"},
+			shards: 2,
+			durations: map[string]time.Duration{
+				"a": 10 * time.Second,
+				"b": 1 * time.Second,
+				"c": 6 * time.Second,
+				"d": 5 * time.Second,
+			},
+			expected: [][]string{{"a", "b"}, {"c", "d"}},
+		},
+		{
+			name:   "unknown durations estimated from the average",
+			tests:  []string{"a", "b", "c"},
+			shards: 2,
+			durations: map[string]time.Duration{
+				"a": 4 * time.Second,
+				"b": 2 * time.Second,
+			},
+			expected: [][]string{{"a"}, {"c", "b"}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, splitTests(test.tests, test.shards, test.durations))
+		})
+	}
+}
+
+func TestMergeShardRecords(t *testing.T) {
+	start := time.Date(2019, 8, 1, 12, 0, 0, 0, time.UTC)
+	args := []string{string(TestTypeSuite), "integration-tests"}
+
+	tests := []struct {
+		name     string
+		records  []TestRecord
+		status   TestStatus
+		message  string
+		exitCode int
+		duration time.Duration
+	}{
+		{
+			name: "all passed",
+			records: []TestRecord{
+				{Status: TestPassed, StartTime: start.Add(time.Second), EndTime: start.Add(10 * time.Second)},
+				{Status: TestPassed, StartTime: start, EndTime: start.Add(20 * time.Second)},
+			},
+			status:   TestPassed,
+			duration: 20 * time.Second,
+		},
+		{
+			name: "one failed",
+			records: []TestRecord{
+				{Status: TestPassed, StartTime: start, EndTime: start.Add(10 * time.Second)},
+				{Status: TestFailed, ExitCode: 1, StartTime: start, EndTime: start.Add(5 * time.Second)},
+			},
+			status:   TestFailed,
+			message:  "1 of 2 shards failed",
+			exitCode: 1,
+			duration: 10 * time.Second,
+		},
+		{
+			name: "cancelled",
+			records: []TestRecord{
+				{Status: TestFailed, ExitCode: 1, StartTime: start, EndTime: start.Add(5 * time.Second)},
+				{Status: TestCancelled, StartTime: start, EndTime: start.Add(8 * time.Second)},
+			},
+			status:   TestCancelled,
+			message:  "test run cancelled",
+			exitCode: 1,
+			duration: 8 * time.Second,
+		},
+		{
+			name: "running",
+			records: []TestRecord{
+				{Status: TestCancelled, StartTime: start, EndTime: start.Add(5 * time.Second)},
+				{Status: TestRunning, StartTime: start},
+			},
+			status: TestRunning,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			merged := mergeShardRecords("test-1", args, test.records)
+			assert.Equal(t, "test-1", merged.TestID)
+			assert.Equal(t, TestTypeSuite, merged.Type)
+			assert.Equal(t, args, merged.Args)
+			assert.Equal(t, test.status, merged.Status)
+			assert.Equal(t, test.message, merged.Message)
+			assert.Equal(t, test.exitCode, merged.ExitCode)
+			assert.Equal(t, start, merged.StartTime)
+			if test.status != TestRunning {
+				assert.Equal(t, test.duration, merged.Duration)
+			}
+		})
+	}
+}
+
+func TestMergeShardRecordsCombinesResults(t *testing.T) {
+	records := []TestRecord{
+		{
+			Status:  TestPassed,
+			Devices: []string{"device-1"},
+			Images:  []ImageInfo{{Image: "onos-config", Digest: "sha256:1"}},
+			Results: []TestResult{{Name: "a", Status: TestPassed}},
+		},
+		{
+			Status:  TestPassed,
+			Devices: []string{"device-2"},
+			Images:  []ImageInfo{{Image: "onos-config", Digest: "sha256:2"}},
+			Results: []TestResult{{Name: "b", Status: TestSkipped}, {Name: "c", Status: TestPassed}},
+		},
+	}
+
+	merged := mergeShardRecords("test-1", []string{string(TestTypeSuite), "suite"}, records)
+	assert.Equal(t, []TestResult{
+		{Name: "a", Status: TestPassed},
+		{Name: "b", Status: TestSkipped},
+		{Name: "c", Status: TestPassed},
+	}, merged.Results)
+	assert.Equal(t, []string{"device-1", "device-2"}, merged.Devices)
+	assert.Equal(t, records[0].Images, merged.Images)
+}
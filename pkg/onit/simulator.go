@@ -16,13 +16,50 @@ package onit
 
 import (
 	"encoding/json"
+	"fmt"
+	"strconv"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
+const (
+	// defaultSimulatorType is the device type with which simulators are registered unless otherwise configured
+	defaultSimulatorType = "Devicesim"
+
+	// defaultSimulatorVersion is the device version with which simulators are registered unless otherwise configured
+	defaultSimulatorVersion = "1.0.0"
+
+	// defaultSimulatorPort is the insecure gNMI port of simulators unless otherwise configured
+	defaultSimulatorPort = 11161
+
+	// simulatorSecurePort is the secure gNMI port of simulators
+	simulatorSecurePort = 10161
+
+	// simulatorParallelism is the number of simulators whose resources are created or deleted concurrently
+	simulatorParallelism = 10
+)
+
+const (
+	// simulatorTypeKey is the simulator ConfigMap key for the simulator's device type
+	simulatorTypeKey = "type"
+
+	// simulatorVersionKey is the simulator ConfigMap key for the simulator's device version
+	simulatorVersionKey = "version"
+
+	// simulatorImageKey is the simulator ConfigMap key for the simulator's image
+	simulatorImageKey = "image"
+
+	// simulatorPortKey is the simulator ConfigMap key for the simulator's insecure gNMI port
+	simulatorPortKey = "port"
+
+	// simulatorFleetKey is the simulator ConfigMap key for the fleet to which the simulator belongs
+	simulatorFleetKey = "fleet"
+)
+
 // GetSimulators returns a list of simulators deployed in the cluster
 func (c *ClusterController) GetSimulators() ([]string, error) {
 	pods, err := c.kubeclient.CoreV1().Pods(c.clusterID).List(metav1.ListOptions{
@@ -40,26 +77,219 @@ func (c *ClusterController) GetSimulators() ([]string, error) {
 	return simulators, nil
 }
 
+// GetFleetSimulators returns a list of the simulators in the fleet with the given prefix
+func (c *ClusterController) GetFleetSimulators(prefix string) ([]string, error) {
+	pods, err := c.kubeclient.CoreV1().Pods(c.clusterID).List(metav1.ListOptions{
+		LabelSelector: "type=simulator,fleet=" + prefix,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	simulators := make([]string, len(pods.Items))
+	for i, pod := range pods.Items {
+		simulators[i] = pod.Name
+	}
+	return simulators, nil
+}
+
+// GetSimulatorNodes returns a list of node info for the simulators deployed in the cluster
+func (c *ClusterController) GetSimulatorNodes() ([]NodeInfo, error) {
+	pods, err := c.kubeclient.CoreV1().Pods(c.clusterID).List(metav1.ListOptions{
+		LabelSelector: "type=simulator",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := make([]NodeInfo, len(pods.Items))
+	for i, pod := range pods.Items {
+		nodes[i] = newNodeInfo(pod, Simulator)
+	}
+	return nodes, nil
+}
+
+// GetSimulatorConfig returns the configuration with which the given simulator was added to the cluster
+func (c *ClusterController) GetSimulatorConfig(name string) (*SimulatorConfig, error) {
+	cm, err := c.kubeclient.CoreV1().ConfigMaps(c.clusterID).Get(name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	return newSimulatorConfig(cm)
+}
+
+// getSimulatorConfigs returns the configurations of the simulators in the cluster, keyed by simulator name
+func (c *ClusterController) getSimulatorConfigs() (map[string]*SimulatorConfig, error) {
+	cms, err := c.kubeclient.CoreV1().ConfigMaps(c.clusterID).List(metav1.ListOptions{
+		LabelSelector: "type=simulator",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	configs := make(map[string]*SimulatorConfig)
+	for _, cm := range cms.Items {
+		config, err := newSimulatorConfig(&cm)
+		if err != nil {
+			return nil, err
+		}
+		configs[cm.Name] = config
+	}
+	return configs, nil
+}
+
+// newSimulatorConfig returns the simulator configuration stored in the given ConfigMap
+func newSimulatorConfig(cm *corev1.ConfigMap) (*SimulatorConfig, error) {
+	config := &SimulatorConfig{
+		Type:    cm.Data[simulatorTypeKey],
+		Version: cm.Data[simulatorVersionKey],
+		Image:   cm.Data[simulatorImageKey],
+		Fleet:   cm.Data[simulatorFleetKey],
+	}
+	if port := cm.Data[simulatorPortKey]; port != "" {
+		var err error
+		config.Port, err = strconv.Atoi(port)
+		if err != nil {
+			return nil, err
+		}
+	}
+	config.setDefaults()
+	return config, nil
+}
+
+// setDefaults sets the default type, version and port of the simulator where not configured
+func (c *SimulatorConfig) setDefaults() {
+	if c.Type == "" {
+		c.Type = defaultSimulatorType
+	}
+	if c.Version == "" {
+		c.Version = defaultSimulatorVersion
+	}
+	if c.Port == 0 {
+		c.Port = defaultSimulatorPort
+	}
+}
+
+// getSimulatorImage returns the image for the given simulator configuration
+func (c *ClusterController) getSimulatorImage(config *SimulatorConfig) string {
+	if config.Image != "" {
+		return config.Image
+	}
+	return c.imageName("onosproject/device-simulator", c.config.ImageTags["simulator"])
+}
+
+// simulatorLabels returns the labels for the resources of the given simulator
+func simulatorLabels(name string, config *SimulatorConfig) map[string]string {
+	labels := map[string]string{
+		"type":      "simulator",
+		"simulator": name,
+	}
+	if config.Fleet != "" {
+		labels["fleet"] = config.Fleet
+	}
+	return labels
+}
+
 // setupSimulator creates a simulator required for the test
 func (c *ClusterController) setupSimulator(name string, config *SimulatorConfig) error {
-	if err := c.createSimulatorConfigMap(name, config); err != nil {
+	config.setDefaults()
+	if err := c.createSimulator(name, 0, config); err != nil {
 		return err
 	}
-	if err := c.createSimulatorPod(name); err != nil {
+	return c.awaitSimulatorReady(name)
+}
+
+// createSimulator creates the resources for the simulator with the given index in its fleet without waiting
+// for it to become ready
+func (c *ClusterController) createSimulator(name string, index int, config *SimulatorConfig) error {
+	if err := c.createSimulatorConfigMap(name, index, config); err != nil {
 		return err
 	}
-	if err := c.createSimulatorService(name); err != nil {
+	if err := c.createSimulatorPod(name, config); err != nil {
 		return err
 	}
-	if err := c.awaitSimulatorReady(name); err != nil {
-		return err
+	return c.createSimulatorService(name, config)
+}
+
+// getFleetSimulatorNames returns the names of count simulators in the fleet with the given prefix
+func getFleetSimulatorNames(prefix string, count int) []string {
+	names := make([]string, count)
+	for i := 0; i < count; i++ {
+		names[i] = fmt.Sprintf("%s-%d", prefix, i)
 	}
-	return nil
+	return names
 }
 
-// createSimulatorConfigMap creates a simulator configuration
-func (c *ClusterController) createSimulatorConfigMap(name string, config *SimulatorConfig) error {
-	configObj, err := config.load()
+// forEachSimulator calls the given function with the index and name of each of the given simulators, running up
+// to simulatorParallelism calls concurrently and reporting the number of completed calls to the given progress function
+func forEachSimulator(names []string, f func(index int, name string) error, progress func(completed int)) error {
+	indexCh := make(chan int, len(names))
+	for i := range names {
+		indexCh <- i
+	}
+	close(indexCh)
+
+	errCh := make(chan error, len(names))
+	workers := simulatorParallelism
+	if len(names) < workers {
+		workers = len(names)
+	}
+	for i := 0; i < workers; i++ {
+		go func() {
+			for i := range indexCh {
+				errCh <- f(i, names[i])
+			}
+		}()
+	}
+
+	var err error
+	for i := 0; i < len(names); i++ {
+		if e := <-errCh; e != nil && err == nil {
+			err = e
+		}
+		progress(i + 1)
+	}
+	return err
+}
+
+// awaitFleetReady blocks until count simulators in the fleet with the given prefix are ready, reporting the number
+// of ready simulators to the given progress function, or until the given timeout elapses
+func (c *ClusterController) awaitFleetReady(prefix string, count int, timeout time.Duration, progress func(ready int)) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		pods, err := c.kubeclient.CoreV1().Pods(c.clusterID).List(metav1.ListOptions{
+			LabelSelector: "type=simulator,fleet=" + prefix,
+		})
+		if err != nil {
+			return err
+		}
+
+		ready := 0
+		for _, pod := range pods.Items {
+			if len(pod.Status.ContainerStatuses) > 0 && pod.Status.ContainerStatuses[0].Ready {
+				ready++
+			}
+		}
+		progress(ready)
+		if ready >= count {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out after %s with %d/%d simulators ready", timeout, ready, count)
+		}
+		time.Sleep(time.Second)
+	}
+}
+
+// createSimulatorConfigMap creates a simulator configuration, rendering the configuration template for the
+// simulator with the given index in its fleet
+func (c *ClusterController) createSimulatorConfigMap(name string, index int, config *SimulatorConfig) error {
+	configObj, err := config.load(SimulatorTemplateData{
+		Name:      name,
+		Index:     index,
+		ClusterID: c.clusterID,
+	})
 	if err != nil {
 		return err
 	}
@@ -71,9 +301,15 @@ func (c *ClusterController) createSimulatorConfigMap(name string, config *Simula
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      name,
 			Namespace: c.clusterID,
+			Labels:    simulatorLabels(name, config),
 		},
 		Data: map[string]string{
-			"config.json": string(configJSON),
+			"config.json":       string(configJSON),
+			simulatorTypeKey:    config.Type,
+			simulatorVersionKey: config.Version,
+			simulatorImageKey:   config.Image,
+			simulatorPortKey:    strconv.Itoa(config.Port),
+			simulatorFleetKey:   config.Fleet,
 		},
 	}
 	_, err = c.kubeclient.CoreV1().ConfigMaps(c.clusterID).Create(cm)
@@ -81,47 +317,44 @@ func (c *ClusterController) createSimulatorConfigMap(name string, config *Simula
 }
 
 // createSimulatorPod creates a simulator pod
-func (c *ClusterController) createSimulatorPod(name string) error {
+func (c *ClusterController) createSimulatorPod(name string, config *SimulatorConfig) error {
 
 	pod := &corev1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      name,
 			Namespace: c.clusterID,
-			Labels: map[string]string{
-				"type":      "simulator",
-				"simulator": name,
-			},
+			Labels:    simulatorLabels(name, config),
 		},
 		Spec: corev1.PodSpec{
 			Containers: []corev1.Container{
 				{
 					Name:            "onos-device-simulator",
-					Image:           c.imageName("onosproject/device-simulator", c.config.ImageTags["simulator"]),
+					Image:           c.getSimulatorImage(config),
 					ImagePullPolicy: c.config.PullPolicy,
 					Env: []corev1.EnvVar{
 						{
 							Name:  "GNMI_PORT",
-							Value: "10161",
+							Value: strconv.Itoa(simulatorSecurePort),
 						},
 						{
 							Name:  "GNMI_INSECURE_PORT",
-							Value: "11161",
+							Value: strconv.Itoa(config.Port),
 						},
 					},
 					Ports: []corev1.ContainerPort{
 						{
 							Name:          "secure",
-							ContainerPort: 10161,
+							ContainerPort: simulatorSecurePort,
 						},
 						{
 							Name:          "insecure",
-							ContainerPort: 11161,
+							ContainerPort: int32(config.Port),
 						},
 					},
 					ReadinessProbe: &corev1.Probe{
 						Handler: corev1.Handler{
 							TCPSocket: &corev1.TCPSocketAction{
-								Port: intstr.FromInt(11161),
+								Port: intstr.FromInt(config.Port),
 							},
 						},
 						InitialDelaySeconds: 5,
@@ -130,7 +363,7 @@ func (c *ClusterController) createSimulatorPod(name string) error {
 					LivenessProbe: &corev1.Probe{
 						Handler: corev1.Handler{
 							TCPSocket: &corev1.TCPSocketAction{
-								Port: intstr.FromInt(11161),
+								Port: intstr.FromInt(config.Port),
 							},
 						},
 						InitialDelaySeconds: 15,
@@ -165,7 +398,7 @@ func (c *ClusterController) createSimulatorPod(name string) error {
 }
 
 // createSimulatorService creates a simulator service
-func (c *ClusterController) createSimulatorService(name string) error {
+func (c *ClusterController) createSimulatorService(name string, config *SimulatorConfig) error {
 
 	service := &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
@@ -179,11 +412,11 @@ func (c *ClusterController) createSimulatorService(name string) error {
 			Ports: []corev1.ServicePort{
 				{
 					Name: "secure",
-					Port: 10161,
+					Port: simulatorSecurePort,
 				},
 				{
 					Name: "insecure",
-					Port: 11161,
+					Port: int32(config.Port),
 				},
 			},
 		},
@@ -207,16 +440,30 @@ func (c *ClusterController) awaitSimulatorReady(name string) error {
 	}
 }
 
-// teardownSimulator tears down a simulator by name
+// newSimulatorDevice returns the onos-topo device for the given simulator
+func newSimulatorDevice(name string, config *SimulatorConfig) *Device {
+	return &Device{
+		ID:      name,
+		Address: fmt.Sprintf("%s:%d", name, config.Port),
+		Type:    config.Type,
+		Version: config.Version,
+		TLS: DeviceTLS{
+			Plain: true,
+		},
+	}
+}
+
+// teardownSimulator tears down a simulator by name, ignoring resources that don't exist so that partially
+// created simulators can be torn down
 func (c *ClusterController) teardownSimulator(name string) error {
 	var err error
-	if e := c.deleteSimulatorPod(name); e != nil {
+	if e := c.deleteSimulatorPod(name); e != nil && !k8serrors.IsNotFound(e) {
 		err = e
 	}
-	if e := c.deleteSimulatorService(name); e != nil {
+	if e := c.deleteSimulatorService(name); e != nil && !k8serrors.IsNotFound(e) {
 		err = e
 	}
-	if e := c.deleteSimulatorConfigMap(name); e != nil {
+	if e := c.deleteSimulatorConfigMap(name); e != nil && !k8serrors.IsNotFound(e) {
 		err = e
 	}
 	return err
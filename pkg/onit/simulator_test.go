@@ -0,0 +1,59 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package onit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewSimulatorDevice(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   *SimulatorConfig
+		expected *Device
+	}{
+		{
+			name:   "defaults",
+			config: &SimulatorConfig{},
+			expected: &Device{
+				ID:      "sim-1",
+				Address: "sim-1:11161",
+				Type:    "Devicesim",
+				Version: "1.0.0",
+				TLS:     DeviceTLS{Plain: true},
+			},
+		},
+		{
+			name:   "custom type",
+			config: &SimulatorConfig{Type: "TestDevice", Version: "2.0.0", Port: 10161},
+			expected: &Device{
+				ID:      "sim-1",
+				Address: "sim-1:10161",
+				Type:    "TestDevice",
+				Version: "2.0.0",
+				TLS:     DeviceTLS{Plain: true},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.config.setDefaults()
+			assert.Equal(t, test.expected, newSimulatorDevice("sim-1", test.config))
+		})
+	}
+}
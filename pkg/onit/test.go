@@ -15,14 +15,26 @@
 package onit
 
 import (
+	"bufio"
+	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"os"
+	"os/signal"
+	"regexp"
+	"strconv"
 	"strings"
+	"sync"
+	"syscall"
 	"time"
 
+	"github.com/onosproject/onos-test/pkg/onit/console"
 	"github.com/onosproject/onos-test/test/env"
+	"gopkg.in/yaml.v1"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -38,35 +50,134 @@ const (
 
 	// TestFailed failed
 	TestFailed TestStatus = "FAILED"
+
+	// TestSkipped skipped
+	TestSkipped TestStatus = "SKIPPED"
+
+	// TestCancelled cancelled
+	TestCancelled TestStatus = "CANCELLED"
+)
+
+// TestType test type
+type TestType string
+
+const (
+	// TestTypeTest a run of one or more tests
+	TestTypeTest TestType = "test"
+
+	// TestTypeSuite a run of one or more test suites
+	TestTypeSuite TestType = "test-suite"
+
+	// TestTypeBench a run of one or more benchmarks
+	TestTypeBench TestType = "bench"
+
+	// TestTypeBenchSuite a run of one or more benchmark suites
+	TestTypeBenchSuite TestType = "bench-suite"
 )
 
 // TestRecord contains information about a test run
 type TestRecord struct {
-	TestID   string
-	Args     []string
-	Status   TestStatus
-	Message  string
-	ExitCode int
+	TestID    string         `json:"testId"`
+	Type      TestType       `json:"type"`
+	Args      []string       `json:"args"`
+	Status    TestStatus     `json:"status"`
+	Message   string         `json:"message"`
+	ExitCode  int            `json:"exitCode"`
+	StartTime time.Time      `json:"startTime"`
+	EndTime   time.Time      `json:"endTime"`
+	Duration  time.Duration  `json:"duration"`
+	Results   []TestResult   `json:"results"`
+	Devices   []string       `json:"devices"`
+	Images    []ImageInfo    `json:"images"`
+	Config    *ClusterConfig `json:"config"`
+}
+
+// TestResult contains the outcome of a single test within a test run
+type TestResult struct {
+	Name     string        `json:"name"`
+	Status   TestStatus    `json:"status"`
+	Duration time.Duration `json:"duration"`
+}
+
+// ImageInfo contains information about an image deployed in the cluster during a test run
+type ImageInfo struct {
+	Image  string `json:"image"`
+	Digest string `json:"digest"`
 }
 
-// startTests starts running a test job
-func (c *ClusterController) startTests(testID string, tests []string, timeout time.Duration) (corev1.Pod, error) {
-	if err := c.createTestJob(testID, tests, timeout); err != nil {
+// testResultPattern matches the result lines output by the Go testing package
+var testResultPattern = regexp.MustCompile(`^\s*--- (PASS|FAIL|SKIP): (\S+) \(([0-9.]+)s\)`)
+
+// errInterrupted is returned when the user interrupts a test run before its test job is running
+var errInterrupted = errors.New("test run cancelled")
+
+// notifyInterrupt returns a channel that's closed when the user interrupts the process, and a function to stop
+// listening for interrupts. Once stopped, interrupts are handled by the default signal handler.
+func notifyInterrupt() (<-chan struct{}, func()) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	interrupted, stopped := make(chan struct{}), make(chan struct{})
+	go func() {
+		select {
+		case <-signals:
+			fmt.Println()
+			close(interrupted)
+		case <-stopped:
+		}
+	}()
+
+	once := &sync.Once{}
+	return interrupted, func() {
+		once.Do(func() {
+			signal.Stop(signals)
+			close(stopped)
+		})
+	}
+}
+
+// startTests starts running a test job, returning errInterrupted if done is closed before the job is running
+func (c *ClusterController) startTests(testID string, tests []string, devices []string, deviceCount int, timeout time.Duration, done <-chan struct{}) (corev1.Pod, error) {
+	if err := c.createTestJob(testID, tests, devices, deviceCount, timeout); err != nil {
 		return corev1.Pod{}, err
 	}
-	pod, err := c.awaitTestJobRunning(testID)
+	pod, err := c.awaitTestJobRunning(testID, done)
 	if err != nil {
 		return corev1.Pod{}, err
 	}
 	return pod, nil
 }
 
-// createTestJob creates the job to run tests
-func (c *ClusterController) createTestJob(testID string, args []string, timeout time.Duration) error {
-	deviceIds, err := c.getDeviceIds()
+// createTestJob creates the job to run tests on the given devices
+func (c *ClusterController) createTestJob(testID string, args []string, devices []string, deviceCount int, timeout time.Duration) error {
+	job, err := c.newTestJob(testID, args, devices, deviceCount, timeout)
 	if err != nil {
 		return err
 	}
+	_, err = c.kubeclient.BatchV1().Jobs(c.clusterID).Create(job)
+	return err
+}
+
+// newTestJob returns a new job to run tests on the given devices
+func (c *ClusterController) newTestJob(testID string, args []string, devices []string, deviceCount int, timeout time.Duration) (*batchv1.Job, error) {
+	images, err := c.getImages()
+	if err != nil {
+		return nil, err
+	}
+	imagesJSON, err := json.Marshal(images)
+	if err != nil {
+		return nil, err
+	}
+
+	configYAML, err := yaml.Marshal(c.config)
+	if err != nil {
+		return nil, err
+	}
+
+	deviceTypes, err := c.getDeviceTypes(devices)
+	if err != nil {
+		return nil, err
+	}
 
 	one := int32(1)
 	timeoutSeconds := int64(timeout / time.Second)
@@ -75,7 +186,11 @@ func (c *ClusterController) createTestJob(testID string, args []string, timeout
 			Name:      testID,
 			Namespace: c.clusterID,
 			Annotations: map[string]string{
-				"test-args": strings.Join(args, ","),
+				"test-args":         strings.Join(args, ","),
+				"test-images":       string(imagesJSON),
+				"test-config":       string(configYAML),
+				"test-devices":      strings.Join(devices, ","),
+				"test-device-count": strconv.Itoa(deviceCount),
 			},
 		},
 		Spec: batchv1.JobSpec{
@@ -123,7 +238,11 @@ func (c *ClusterController) createTestJob(testID string, args []string, timeout
 								},
 								{
 									Name:  env.TestDevicesEnv,
-									Value: strings.Join(deviceIds, ","),
+									Value: strings.Join(devices, ","),
+								},
+								{
+									Name:  env.TestDeviceTypesEnv,
+									Value: strings.Join(deviceTypes, ","),
 								},
 							},
 							VolumeMounts: []corev1.VolumeMount{
@@ -132,6 +251,11 @@ func (c *ClusterController) createTestJob(testID string, args []string, timeout
 									MountPath: "/etc/onos-config/certs",
 									ReadOnly:  true,
 								},
+								{
+									Name:      "test-secret",
+									MountPath: env.TestCertsPath,
+									ReadOnly:  true,
+								},
 							},
 						},
 					},
@@ -144,24 +268,36 @@ func (c *ClusterController) createTestJob(testID string, args []string, timeout
 								},
 							},
 						},
+						{
+							Name: "test-secret",
+							VolumeSource: corev1.VolumeSource{
+								Secret: &corev1.SecretVolumeSource{
+									SecretName: c.getTestSecretName(),
+								},
+							},
+						},
 					},
 				},
 			},
 		},
 	}
 
-	_, err = c.kubeclient.BatchV1().Jobs(c.clusterID).Create(job)
-	return err
+	return job, nil
 }
 
-// awaitTestJobRunning blocks until the test job creates a pod in the RUNNING state
-func (c *ClusterController) awaitTestJobRunning(testID string) (corev1.Pod, error) {
+// awaitTestJobRunning blocks until the test job creates a pod in the RUNNING state, returning errInterrupted
+// if done is closed first
+func (c *ClusterController) awaitTestJobRunning(testID string, done <-chan struct{}) (corev1.Pod, error) {
 	for {
 		pod, err := c.getPod(testID)
 		if err == nil {
 			return pod, nil
 		}
-		time.Sleep(100 * time.Millisecond)
+		select {
+		case <-done:
+			return corev1.Pod{}, errInterrupted
+		case <-time.After(100 * time.Millisecond):
+		}
 	}
 }
 
@@ -188,28 +324,61 @@ func (c *ClusterController) GetHistory() ([]TestRecord, error) {
 	}
 
 	records := make([]TestRecord, 0, len(jobs.Items))
+	shards := make(map[string][]batchv1.Job)
 	for _, job := range jobs.Items {
+		// The shards of a sharded test run are merged into a single record
+		if testID, ok := job.Labels[shardLabel]; ok {
+			if _, ok := shards[testID]; !ok {
+				records = append(records, TestRecord{TestID: testID})
+			}
+			shards[testID] = append(shards[testID], job)
+			continue
+		}
+
 		record, err := c.getRecord(job)
 		if err != nil {
 			return nil, err
 		}
 		records = append(records, record)
 	}
+
+	for i, record := range records {
+		if jobs, ok := shards[record.TestID]; ok {
+			merged, err := c.getShardedRecord(record.TestID, jobs)
+			if err != nil {
+				return nil, err
+			}
+			records[i] = merged
+		}
+	}
 	return records, nil
 }
 
 // GetRecord returns a single record for the given test
 func (c *ClusterController) GetRecord(testID string) (TestRecord, error) {
 	job, err := c.kubeclient.BatchV1().Jobs(c.clusterID).Get(testID, metav1.GetOptions{})
-	if err != nil {
+	if err == nil {
+		return c.getRecord(*job)
+	} else if !k8serrors.IsNotFound(err) {
 		return TestRecord{}, err
 	}
-	return c.getRecord(*job)
+
+	// If no job exists for the test, look for the shards of a sharded test run
+	shards, listErr := c.getShardJobs(testID)
+	if listErr != nil {
+		return TestRecord{}, listErr
+	} else if len(shards) == 0 {
+		return TestRecord{}, err
+	}
+	return c.getShardedRecord(testID, shards)
 }
 
-// GetRecord returns a single record for the given test
+// getRecord returns the record of the test run in the given job
 func (c *ClusterController) getRecord(job batchv1.Job) (TestRecord, error) {
 	testID := job.Labels["test"]
+	if testID == "" {
+		testID = job.Name
+	}
 
 	var args []string
 	testArgs, ok := job.Annotations["test-args"]
@@ -219,17 +388,68 @@ func (c *ClusterController) getRecord(job batchv1.Job) (TestRecord, error) {
 		args = make([]string, 0)
 	}
 
-	pod, err := c.getPod(testID)
-	if err != nil {
-		return TestRecord{}, nil
-	}
-
 	record := TestRecord{
 		TestID: testID,
 		Args:   args,
 	}
+	if len(args) > 0 {
+		record.Type = TestType(args[0])
+	}
+
+	if devices := job.Annotations["test-devices"]; devices != "" {
+		record.Devices = strings.Split(devices, ",")
+	}
+
+	if imagesJSON, ok := job.Annotations["test-images"]; ok {
+		if err := json.Unmarshal([]byte(imagesJSON), &record.Images); err != nil {
+			return TestRecord{}, err
+		}
+	}
+
+	if configYAML, ok := job.Annotations["test-config"]; ok {
+		config, err := decodeClusterConfig([]byte(configYAML))
+		if err != nil {
+			return TestRecord{}, err
+		}
+		record.Config = config
+	}
+
+	if job.Status.StartTime != nil {
+		record.StartTime = job.Status.StartTime.Time
+	}
 
-	state := pod.Status.ContainerStatuses[0].State
+	// Cancelled test runs retain their job but not their pods
+	if TestStatus(job.Annotations["test-status"]) == TestCancelled {
+		record.Status = TestCancelled
+		record.Message = "test run cancelled"
+		if cancelTime, err := time.Parse(time.RFC3339, job.Annotations["test-cancelled"]); err == nil {
+			record.EndTime = cancelTime
+			record.Duration = record.EndTime.Sub(record.StartTime)
+		}
+		return record, nil
+	}
+
+	// If the pod has been removed, fall back to the status of the job
+	pod, err := c.getPod(testID)
+	if err != nil || len(pod.Status.ContainerStatuses) == 0 {
+		if job.Status.Succeeded > 0 {
+			record.Status = TestPassed
+		} else if job.Status.Failed > 0 {
+			record.Status = TestFailed
+		} else {
+			record.Status = TestRunning
+		}
+		if job.Status.CompletionTime != nil {
+			record.EndTime = job.Status.CompletionTime.Time
+			record.Duration = record.EndTime.Sub(record.StartTime)
+		}
+		return record, nil
+	}
+
+	status := pod.Status.ContainerStatuses[0]
+	record.Images = append(record.Images, newImageInfo(status.Image, status.ImageID))
+
+	state := status.State
 	if state.Terminated != nil {
 		record.Message = state.Terminated.Message
 		record.ExitCode = int(state.Terminated.ExitCode)
@@ -238,13 +458,201 @@ func (c *ClusterController) getRecord(job batchv1.Job) (TestRecord, error) {
 		} else {
 			record.Status = TestFailed
 		}
+		if record.StartTime.IsZero() {
+			record.StartTime = state.Terminated.StartedAt.Time
+		}
+		record.EndTime = state.Terminated.FinishedAt.Time
+		record.Duration = record.EndTime.Sub(record.StartTime)
+
+		// Results are persisted in the job when the run completes. Runs whose results weren't persisted are
+		// parsed from the logs, and if the logs can't be read, the record is returned without results.
+		if results, ok := getRecordedTestResults(job); ok {
+			record.Results = results
+		} else {
+			record.Results, _ = c.getTestResults(pod)
+		}
 	} else {
 		record.Status = TestRunning
+		if !record.StartTime.IsZero() {
+			record.Duration = time.Since(record.StartTime)
+		}
 	}
 
 	return record, nil
 }
 
+// getRecordedTestResults returns the per-test results recorded in the given job, if any
+func getRecordedTestResults(job batchv1.Job) ([]TestResult, bool) {
+	resultsJSON, ok := job.Annotations["test-results"]
+	if !ok {
+		return nil, false
+	}
+	results := make([]TestResult, 0)
+	if err := json.Unmarshal([]byte(resultsJSON), &results); err != nil {
+		return nil, false
+	}
+	return results, true
+}
+
+// getTestResults parses the per-test results from the logs of the given completed test pod
+func (c *ClusterController) getTestResults(pod corev1.Pod) ([]TestResult, error) {
+	logs, err := c.getLogs(pod, corev1.PodLogOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return parseTestResults(logs), nil
+}
+
+// recordTestResults parses the per-test results from the logs of the given completed test pod and records them
+// in the annotations of the test job. Results are recorded once when the test run completes.
+func (c *ClusterController) recordTestResults(testID string, pod corev1.Pod) error {
+	results, err := c.getTestResults(pod)
+	if err != nil {
+		return err
+	}
+	resultsJSON, err := json.Marshal(results)
+	if err != nil {
+		return err
+	}
+	job, err := c.kubeclient.BatchV1().Jobs(c.clusterID).Get(testID, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if job.Annotations == nil {
+		job.Annotations = make(map[string]string)
+	}
+	job.Annotations["test-results"] = string(resultsJSON)
+	_, err = c.kubeclient.BatchV1().Jobs(c.clusterID).Update(job)
+	return err
+}
+
+// parseTestResults parses the per-test results from the output of the test runner
+func parseTestResults(output []byte) []TestResult {
+	results := make([]TestResult, 0)
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		match := testResultPattern.FindStringSubmatch(scanner.Text())
+		if match == nil {
+			continue
+		}
+
+		var status TestStatus
+		switch match[1] {
+		case "PASS":
+			status = TestPassed
+		case "FAIL":
+			status = TestFailed
+		case "SKIP":
+			status = TestSkipped
+		}
+
+		seconds, _ := strconv.ParseFloat(match[3], 64)
+		results = append(results, TestResult{
+			Name:     match[2],
+			Status:   status,
+			Duration: time.Duration(seconds * float64(time.Second)),
+		})
+	}
+	return results
+}
+
+// getImages returns the images and digests of the pods deployed in the cluster
+func (c *ClusterController) getImages() ([]ImageInfo, error) {
+	pods, err := c.kubeclient.CoreV1().Pods(c.clusterID).List(metav1.ListOptions{
+		LabelSelector: "!test",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	images := make([]ImageInfo, 0)
+	found := make(map[string]bool)
+	for _, pod := range pods.Items {
+		for _, status := range pod.Status.ContainerStatuses {
+			if _, ok := found[status.Image]; !ok {
+				images = append(images, newImageInfo(status.Image, status.ImageID))
+				found[status.Image] = true
+			}
+		}
+	}
+	return images, nil
+}
+
+// newImageInfo returns the image info for the given container image and image ID
+func newImageInfo(image string, imageID string) ImageInfo {
+	digest := imageID
+	if i := strings.LastIndex(imageID, "@"); i >= 0 {
+		digest = imageID[i+1:]
+	}
+	return ImageInfo{
+		Image:  image,
+		Digest: digest,
+	}
+}
+
+// getTestArgs returns the arguments, number of devices and timeout with which the given test was run
+func (c *ClusterController) getTestArgs(testID string) ([]string, int, time.Duration, error) {
+	job, err := c.kubeclient.BatchV1().Jobs(c.clusterID).Get(testID, metav1.GetOptions{})
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	testArgs, ok := job.Annotations["test-args"]
+	if !ok || testArgs == "" {
+		return nil, 0, 0, fmt.Errorf("no arguments recorded for test %s", testID)
+	}
+
+	deviceCount, _ := strconv.Atoi(job.Annotations["test-device-count"])
+
+	var timeout time.Duration
+	if job.Spec.ActiveDeadlineSeconds != nil {
+		timeout = time.Duration(*job.Spec.ActiveDeadlineSeconds) * time.Second
+	}
+	return strings.Split(testArgs, ","), deviceCount, timeout, nil
+}
+
+// CancelTests cancels a running test job
+func (c *ClusterController) CancelTests(testID string) console.ErrorStatus {
+	c.status.Start("Cancelling test job: " + testID)
+	if err := c.cancelTestJob(testID); err != nil {
+		return c.status.Fail(err)
+	}
+	return c.status.Succeed()
+}
+
+// cancelTestJob stops the given test job and deletes its pods. The test history is derived from the test jobs
+// in the cluster, so rather than being deleted the job is scaled down to no pods and annotated as cancelled,
+// which keeps the cancelled run in the history and lets its devices be leased by other runs.
+func (c *ClusterController) cancelTestJob(testID string) error {
+	job, err := c.kubeclient.BatchV1().Jobs(c.clusterID).Get(testID, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	if job.Status.CompletionTime != nil || job.Status.Succeeded > 0 || job.Status.Failed > 0 {
+		return fmt.Errorf("test %s is not running", testID)
+	}
+
+	zero := int32(0)
+	job.Spec.Parallelism = &zero
+	if job.Annotations == nil {
+		job.Annotations = make(map[string]string)
+	}
+	job.Annotations["test-status"] = string(TestCancelled)
+	job.Annotations["test-cancelled"] = time.Now().Format(time.RFC3339)
+	if _, err := c.kubeclient.BatchV1().Jobs(c.clusterID).Update(job); err != nil {
+		return err
+	}
+
+	err = c.kubeclient.CoreV1().Pods(c.clusterID).DeleteCollection(&metav1.DeleteOptions{}, metav1.ListOptions{
+		LabelSelector: "test=" + testID,
+	})
+	if err != nil {
+		return err
+	}
+	return c.releaseDevices(testID)
+}
+
 // getPod finds the Pod for the given test
 func (c *ClusterController) getPod(testID string) (corev1.Pod, error) {
 	pods, err := c.kubeclient.CoreV1().Pods(c.clusterID).List(metav1.ListOptions{
@@ -269,7 +677,21 @@ func (c *ClusterController) getPod(testID string) (corev1.Pod, error) {
 
 // getDeviceIds returns a slice of configured simulator device IDs
 func (c *ClusterController) getDeviceIds() ([]string, error) {
-	devices := []string{}
+	devices, err := c.getDevices()
+	if err != nil {
+		return nil, err
+	}
+
+	deviceIds := make([]string, len(devices))
+	for i, device := range devices {
+		deviceIds[i] = device.ID
+	}
+	return deviceIds, nil
+}
+
+// getDevices returns the devices configured in the cluster's device store and the simulators deployed in the cluster
+func (c *ClusterController) getDevices() ([]*Device, error) {
+	devices := []*Device{}
 
 	// Load the cluster configuration
 	config, err := c.config.load()
@@ -281,7 +703,7 @@ func (c *ClusterController) getDeviceIds() ([]string, error) {
 	deviceStoreObj, ok := config["deviceStore"].(map[string]interface{})
 	if ok {
 		for name := range deviceStoreObj["Store"].(map[string]interface{}) {
-			devices = append(devices, name)
+			devices = append(devices, &Device{ID: name})
 		}
 	}
 
@@ -291,8 +713,41 @@ func (c *ClusterController) getDeviceIds() ([]string, error) {
 		return nil, err
 	}
 
-	// Add each simulator to the devices
-	devices = append(devices, simulators...)
-
+	// Add each simulator to the devices with the type and version with which it was added
+	simulatorConfigs, err := c.getSimulatorConfigs()
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range simulators {
+		simulatorConfig, ok := simulatorConfigs[name]
+		if !ok {
+			simulatorConfig, err = c.GetSimulatorConfig(name)
+			if err != nil {
+				return nil, err
+			}
+		}
+		devices = append(devices, newSimulatorDevice(name, simulatorConfig))
+	}
 	return devices, nil
 }
+
+// getDeviceTypes returns the type:version of each of the given devices that has a known type
+func (c *ClusterController) getDeviceTypes(deviceIds []string) ([]string, error) {
+	devices, err := c.getDevices()
+	if err != nil {
+		return nil, err
+	}
+
+	reserved := make(map[string]bool)
+	for _, id := range deviceIds {
+		reserved[id] = true
+	}
+
+	types := []string{}
+	for _, device := range devices {
+		if reserved[device.ID] && device.Type != "" {
+			types = append(types, fmt.Sprintf("%s=%s:%s", device.ID, device.Type, device.Version))
+		}
+	}
+	return types, nil
+}
@@ -0,0 +1,167 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package onit
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/onosproject/onos-topo/pkg/northbound/proto"
+	"google.golang.org/grpc"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// topoPort is the gRPC port of the onos-topo service
+	topoPort = 5150
+
+	// topoTimeout is the timeout for requests to the onos-topo service
+	topoTimeout = 15 * time.Second
+)
+
+// topoClient is a client for the onos-topo device service
+type topoClient struct {
+	conn   *grpc.ClientConn
+	client proto.DeviceServiceClient
+	stop   chan struct{}
+}
+
+// close closes the client's connection and stops forwarding its port
+func (t *topoClient) close() {
+	t.conn.Close()
+	close(t.stop)
+}
+
+// newTopoClient returns a new client for the onos-topo device service
+func (c *ClusterController) newTopoClient() (*topoClient, error) {
+	conn, stop, err := c.dialService(OnosTopo, "onos-topo", topoPort)
+	if err != nil {
+		return nil, err
+	}
+	return &topoClient{
+		conn:   conn,
+		client: proto.NewDeviceServiceClient(conn),
+		stop:   stop,
+	}, nil
+}
+
+// addDevices adds the given devices to onos-topo. The vendored onos-topo device API cannot carry device types
+// and roles, so devices are added via the CLI if a CLI node is available, and over a single connection to
+// the onos-topo device service otherwise.
+func (c *ClusterController) addDevices(devices ...*Device) error {
+	if pod, ok := c.getCliPod(); ok {
+		for _, device := range devices {
+			if err := c.addDeviceViaCli(pod, device); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	client, err := c.newTopoClient()
+	if err != nil {
+		return err
+	}
+	defer client.close()
+
+	for _, device := range devices {
+		if err := client.addDevice(device); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getCliPod returns a running onos-cli pod, if the CLI is enabled and one is available
+func (c *ClusterController) getCliPod() (corev1.Pod, bool) {
+	if !c.config.IsEnabled(string(OnosCli)) {
+		return corev1.Pod{}, false
+	}
+	pods, err := c.kubeclient.CoreV1().Pods(c.clusterID).List(metav1.ListOptions{
+		LabelSelector: "app=onos,type=cli",
+	})
+	if err != nil {
+		return corev1.Pod{}, false
+	}
+	for _, pod := range pods.Items {
+		if pod.Status.Phase == corev1.PodRunning {
+			return pod, true
+		}
+	}
+	return corev1.Pod{}, false
+}
+
+// addDeviceViaCli adds the given device to onos-topo by executing the onos CLI in the given pod
+func (c *ClusterController) addDeviceViaCli(pod corev1.Pod, device *Device) error {
+	_, stderr, err := c.executeStreams(pod, device.toCliCommand())
+	if err != nil {
+		if message := strings.TrimSpace(string(stderr)); message != "" {
+			err = errors.New(message)
+		}
+		return &DeviceError{Op: DeviceAdd, Device: device.ID, Err: err}
+	}
+	return nil
+}
+
+// removeDevices removes the devices with the given IDs from onos-topo over a single connection
+func (c *ClusterController) removeDevices(ids ...string) error {
+	client, err := c.newTopoClient()
+	if err != nil {
+		return err
+	}
+	defer client.close()
+
+	for _, id := range ids {
+		if err := client.removeDevice(id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addDevice adds the given device to onos-topo
+func (t *topoClient) addDevice(device *Device) error {
+	ctx, cancel := context.WithTimeout(context.Background(), topoTimeout)
+	defer cancel()
+	_, err := t.client.Add(ctx, &proto.AddDeviceRequest{
+		Device: device.toProto(),
+	})
+	if err != nil {
+		return &DeviceError{Op: DeviceAdd, Device: device.ID, Err: err}
+	}
+	return nil
+}
+
+// removeDevice removes the device with the given ID from onos-topo
+func (t *topoClient) removeDevice(id string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), topoTimeout)
+	defer cancel()
+	response, err := t.client.Get(ctx, &proto.GetDeviceRequest{
+		DeviceId: id,
+	})
+	if err != nil {
+		return &DeviceError{Op: DeviceRemove, Device: id, Err: err}
+	}
+	_, err = t.client.Remove(ctx, &proto.RemoveDeviceRequest{
+		Device: response.Device,
+	})
+	if err != nil {
+		return &DeviceError{Op: DeviceRemove, Device: id, Err: err}
+	}
+	return nil
+}
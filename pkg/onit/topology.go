@@ -0,0 +1,260 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package onit
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ghodss/yaml"
+)
+
+const (
+	// customTopoName is the name under which custom topologies are registered with Mininet
+	customTopoName = "onit"
+
+	// customTopoFile is the name of the generated Mininet custom topology file in the network ConfigMap
+	customTopoFile = "topo.py"
+)
+
+// nodeNamePattern is the pattern to which the names of switches and hosts in custom topologies must conform
+var nodeNamePattern = regexp.MustCompile("^[a-z][a-z0-9]*$")
+
+// Topology is the set of switches, hosts and links in a Mininet network
+type Topology struct {
+	Switches []string `yaml:"switches" json:"switches"`
+	Hosts    []string `yaml:"hosts" json:"hosts"`
+	Links    []Link   `yaml:"links" json:"links"`
+}
+
+// Link is a link between two nodes in a Mininet network
+type Link struct {
+	Source string `yaml:"source" json:"source"`
+	Target string `yaml:"target" json:"target"`
+}
+
+// LoadTopology loads a custom topology from the given YAML or JSON file
+func LoadTopology(path string) (*Topology, error) {
+	bytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	topology := &Topology{}
+	if err := yaml.Unmarshal(bytes, topology); err != nil {
+		return nil, err
+	}
+	if err := topology.validate(); err != nil {
+		return nil, fmt.Errorf("invalid topology %s: %v", path, err)
+	}
+	return topology, nil
+}
+
+// validate validates a custom topology
+func (t *Topology) validate() error {
+	if len(t.Switches) == 0 {
+		return fmt.Errorf("topology must contain at least one switch")
+	}
+
+	nodes := make(map[string]bool)
+	for _, node := range append(append([]string{}, t.Switches...), t.Hosts...) {
+		if !nodeNamePattern.MatchString(node) {
+			return fmt.Errorf("invalid node name %s; names must be lowercase alphanumeric and start with a letter", node)
+		}
+		if nodes[node] {
+			return fmt.Errorf("duplicate node %s", node)
+		}
+		nodes[node] = true
+	}
+	for _, link := range t.Links {
+		if !nodes[link.Source] {
+			return fmt.Errorf("unknown link source %s", link.Source)
+		}
+		if !nodes[link.Target] {
+			return fmt.Errorf("unknown link target %s", link.Target)
+		}
+	}
+	return nil
+}
+
+// addSwitch adds a switch to the topology and returns its name
+func (t *Topology) addSwitch(name string) string {
+	t.Switches = append(t.Switches, name)
+	return name
+}
+
+// addHost adds a host to the topology and returns its name
+func (t *Topology) addHost(name string) string {
+	t.Hosts = append(t.Hosts, name)
+	return name
+}
+
+// addLink adds a link between the given nodes to the topology
+func (t *Topology) addLink(source string, target string) {
+	t.Links = append(t.Links, Link{Source: source, Target: target})
+}
+
+// newSingleTopology returns a topology of a single switch with k hosts
+func newSingleTopology(k int) *Topology {
+	t := &Topology{}
+	s := t.addSwitch("s1")
+	for i := 1; i <= k; i++ {
+		t.addLink(t.addHost(fmt.Sprintf("h%d", i)), s)
+	}
+	return t
+}
+
+// newLinearTopology returns a topology of k linearly connected switches with n hosts per switch
+func newLinearTopology(k int, n int) *Topology {
+	t := &Topology{}
+	last := ""
+	for i := 1; i <= k; i++ {
+		s := t.addSwitch(fmt.Sprintf("s%d", i))
+		for j := 1; j <= n; j++ {
+			host := fmt.Sprintf("h%d", i)
+			if n > 1 {
+				host = fmt.Sprintf("h%ds%d", j, i)
+			}
+			t.addLink(t.addHost(host), s)
+		}
+		if last != "" {
+			t.addLink(s, last)
+		}
+		last = s
+	}
+	return t
+}
+
+// newTreeTopology returns a tree topology of the given depth and fanout, numbering switches and hosts depth-first
+func newTreeTopology(depth int, fanout int) *Topology {
+	t := &Topology{}
+	switchNum, hostNum := 1, 1
+	var addTree func(depth int) string
+	addTree = func(depth int) string {
+		if depth == 0 {
+			host := t.addHost(fmt.Sprintf("h%d", hostNum))
+			hostNum++
+			return host
+		}
+		s := t.addSwitch(fmt.Sprintf("s%d", switchNum))
+		switchNum++
+		for i := 0; i < fanout; i++ {
+			t.addLink(s, addTree(depth-1))
+		}
+		return s
+	}
+	addTree(depth)
+	return t
+}
+
+// newTorusTopology returns an x by y torus of switches with n hosts per switch
+func newTorusTopology(x int, y int, n int) *Topology {
+	t := &Topology{}
+	switches := make([][]string, x)
+	for i := 0; i < x; i++ {
+		switches[i] = make([]string, y)
+		for j := 0; j < y; j++ {
+			loc := fmt.Sprintf("%dx%d", i+1, j+1)
+			s := t.addSwitch("s" + loc)
+			switches[i][j] = s
+			for k := 1; k <= n; k++ {
+				host := "h" + loc
+				if n > 1 {
+					host = fmt.Sprintf("h%sx%d", loc, k)
+				}
+				t.addLink(t.addHost(host), s)
+			}
+		}
+	}
+	for i := 0; i < x; i++ {
+		for j := 0; j < y; j++ {
+			t.addLink(switches[i][j], switches[i][(j+1)%y])
+			t.addLink(switches[i][j], switches[(i+1)%x][j])
+		}
+	}
+	return t
+}
+
+// getSwitchPorts returns the gRPC port of each switch in the topology. Mininet starts switches in natural
+// name order and each stratum switch takes the next port from 50001.
+func (t *Topology) getSwitchPorts() map[string]int {
+	switches := append([]string{}, t.Switches...)
+	sort.SliceStable(switches, func(i, j int) bool {
+		return naturalLess(switches[i], switches[j])
+	})
+
+	ports := make(map[string]int)
+	for i, s := range switches {
+		ports[s] = stratumPort + i
+	}
+	return ports
+}
+
+// newCustomTopologyScript returns a Mininet custom topology script that builds the topology
+func (t *Topology) newCustomTopologyScript() []byte {
+	var buf bytes.Buffer
+	buf.WriteString("from mininet.topo import Topo\n\n\n")
+	buf.WriteString("class OnitTopo(Topo):\n")
+	buf.WriteString("    def build(self):\n")
+	for _, s := range t.Switches {
+		fmt.Fprintf(&buf, "        self.addSwitch('%s')\n", s)
+	}
+	for _, h := range t.Hosts {
+		fmt.Fprintf(&buf, "        self.addHost('%s')\n", h)
+	}
+	for _, link := range t.Links {
+		fmt.Fprintf(&buf, "        self.addLink('%s', '%s')\n", link.Source, link.Target)
+	}
+	buf.WriteString("        pass\n\n\n")
+	fmt.Fprintf(&buf, "topos = {'%s': OnitTopo}\n", customTopoName)
+	return buf.Bytes()
+}
+
+// naturalLess returns whether a sorts before b when runs of digits are compared numerically
+func naturalLess(a string, b string) bool {
+	for a != "" && b != "" {
+		aDigits, bDigits := leadingDigits(a), leadingDigits(b)
+		if aDigits != "" && bDigits != "" {
+			aNum, _ := strconv.Atoi(aDigits)
+			bNum, _ := strconv.Atoi(bDigits)
+			if aNum != bNum {
+				return aNum < bNum
+			}
+			a, b = a[len(aDigits):], b[len(bDigits):]
+			continue
+		}
+		if a[0] != b[0] {
+			return a[0] < b[0]
+		}
+		a, b = a[1:], b[1:]
+	}
+	return len(a) < len(b)
+}
+
+// leadingDigits returns the run of digits at the start of s
+func leadingDigits(s string) string {
+	i := strings.IndexFunc(s, func(r rune) bool {
+		return r < '0' || r > '9'
+	})
+	if i < 0 {
+		return s
+	}
+	return s[:i]
+}
@@ -0,0 +1,253 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package onit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTopologyGenerators(t *testing.T) {
+	tests := []struct {
+		name     string
+		topology *Topology
+		expected *Topology
+	}{
+		{
+			name:     "single",
+			topology: newSingleTopology(2),
+			expected: &Topology{
+				Switches: []string{"s1"},
+				Hosts:    []string{"h1", "h2"},
+				Links:    []Link{{"h1", "s1"}, {"h2", "s1"}},
+			},
+		},
+		{
+			name:     "linear",
+			topology: newLinearTopology(3, 1),
+			expected: &Topology{
+				Switches: []string{"s1", "s2", "s3"},
+				Hosts:    []string{"h1", "h2", "h3"},
+				Links:    []Link{{"h1", "s1"}, {"h2", "s2"}, {"s2", "s1"}, {"h3", "s3"}, {"s3", "s2"}},
+			},
+		},
+		{
+			name:     "linear with multiple hosts per switch",
+			topology: newLinearTopology(2, 2),
+			expected: &Topology{
+				Switches: []string{"s1", "s2"},
+				Hosts:    []string{"h1s1", "h2s1", "h1s2", "h2s2"},
+				Links:    []Link{{"h1s1", "s1"}, {"h2s1", "s1"}, {"h1s2", "s2"}, {"h2s2", "s2"}, {"s2", "s1"}},
+			},
+		},
+		{
+			name:     "tree",
+			topology: newTreeTopology(2, 2),
+			expected: &Topology{
+				Switches: []string{"s1", "s2", "s3"},
+				Hosts:    []string{"h1", "h2", "h3", "h4"},
+				Links:    []Link{{"s2", "h1"}, {"s2", "h2"}, {"s1", "s2"}, {"s3", "h3"}, {"s3", "h4"}, {"s1", "s3"}},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, test.topology)
+			assert.NoError(t, test.topology.validate())
+		})
+	}
+}
+
+func TestTorusTopology(t *testing.T) {
+	tests := []struct {
+		name  string
+		x     int
+		y     int
+		n     int
+		hosts []string
+	}{
+		{name: "one host per switch", x: 3, y: 3, n: 1, hosts: []string{"h1x1", "h1x2"}},
+		{name: "multiple hosts per switch", x: 3, y: 4, n: 2, hosts: []string{"h1x1x1", "h1x1x2"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			topology := newTorusTopology(test.x, test.y, test.n)
+			switches := test.x * test.y
+			assert.Len(t, topology.Switches, switches)
+			assert.Len(t, topology.Hosts, switches*test.n)
+			assert.Len(t, topology.Links, switches*test.n+2*switches)
+			assert.Equal(t, "s1x1", topology.Switches[0])
+			assert.Equal(t, test.hosts, topology.Hosts[:2])
+			assert.NoError(t, topology.validate())
+
+			// Each switch is linked to its neighbors in both dimensions, wrapping around at the edges
+			degrees := make(map[string]int)
+			for _, link := range topology.Links {
+				degrees[link.Source]++
+				degrees[link.Target]++
+			}
+			for _, s := range topology.Switches {
+				assert.Equal(t, 4+test.n, degrees[s], s)
+			}
+		})
+	}
+}
+
+func TestGetSwitchPorts(t *testing.T) {
+	tests := []struct {
+		name     string
+		switches []string
+		expected map[string]int
+	}{
+		{
+			name:     "natural order",
+			switches: []string{"s10", "s2", "s1"},
+			expected: map[string]int{"s1": 50001, "s2": 50002, "s10": 50003},
+		},
+		{
+			name:     "torus",
+			switches: []string{"s1x1", "s1x10", "s1x2", "s2x1"},
+			expected: map[string]int{"s1x1": 50001, "s1x2": 50002, "s1x10": 50003, "s2x1": 50004},
+		},
+		{
+			name:     "custom names",
+			switches: []string{"spine", "leaf2", "leaf1"},
+			expected: map[string]int{"leaf1": 50001, "leaf2": 50002, "spine": 50003},
+		},
+		{
+			name:     "no switches",
+			switches: []string{},
+			expected: map[string]int{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			topology := &Topology{Switches: test.switches}
+			assert.Equal(t, test.expected, topology.getSwitchPorts())
+		})
+	}
+}
+
+func TestNaturalLess(t *testing.T) {
+	tests := []struct {
+		a    string
+		b    string
+		less bool
+	}{
+		{a: "s1", b: "s2", less: true},
+		{a: "s2", b: "s10", less: true},
+		{a: "s10", b: "s2", less: false},
+		{a: "s1", b: "s1", less: false},
+		{a: "s1x2", b: "s1x10", less: true},
+		{a: "s2x1", b: "s1x10", less: false},
+		{a: "h1", b: "s1", less: true},
+		{a: "s", b: "s1", less: true},
+		{a: "s1", b: "s", less: false},
+		{a: "s01", b: "s1", less: false},
+		{a: "s1", b: "s01", less: false},
+		{a: "", b: "s1", less: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.a+"<"+test.b, func(t *testing.T) {
+			assert.Equal(t, test.less, naturalLess(test.a, test.b))
+		})
+	}
+}
+
+func TestParseTopology(t *testing.T) {
+	tests := []struct {
+		arg      string
+		topoType TopoType
+		switches int
+		hosts    int
+		valid    bool
+	}{
+		{arg: "", topoType: Single, switches: 1, hosts: 2, valid: true},
+		{arg: "single,3", topoType: Single, switches: 1, hosts: 3, valid: true},
+		{arg: "minimal", topoType: Single, switches: 1, hosts: 2, valid: true},
+		{arg: "linear", topoType: Linear, switches: 2, hosts: 2, valid: true},
+		{arg: "linear,3", topoType: Linear, switches: 3, hosts: 3, valid: true},
+		{arg: "linear,k=3,n=2", topoType: Linear, switches: 3, hosts: 6, valid: true},
+		{arg: "tree", topoType: Tree, switches: 1, hosts: 2, valid: true},
+		{arg: "tree,depth=2,fanout=3", topoType: Tree, switches: 4, hosts: 9, valid: true},
+		{arg: "torus,3,3", topoType: Torus, switches: 9, hosts: 9, valid: true},
+		{arg: "torus,3,4,2", topoType: Torus, switches: 12, hosts: 24, valid: true},
+		{arg: "torus,3", valid: false},
+		{arg: "torus,2,3", valid: false},
+		{arg: "linear,x", valid: false},
+		{arg: "linear,0", valid: false},
+		{arg: "ring,3", valid: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.arg, func(t *testing.T) {
+			topology, topoType, err := parseTopology(test.arg)
+			if !test.valid {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.topoType, topoType)
+			assert.Len(t, topology.Switches, test.switches)
+			assert.Len(t, topology.Hosts, test.hosts)
+		})
+	}
+}
+
+func TestValidateTopology(t *testing.T) {
+	tests := []struct {
+		name     string
+		topology *Topology
+		valid    bool
+	}{
+		{
+			name:     "valid",
+			topology: &Topology{Switches: []string{"s1", "s2"}, Hosts: []string{"h1"}, Links: []Link{{"s1", "s2"}, {"h1", "s1"}}},
+			valid:    true,
+		},
+		{
+			name:     "no switches",
+			topology: &Topology{Hosts: []string{"h1"}},
+		},
+		{
+			name:     "invalid name",
+			topology: &Topology{Switches: []string{"S1"}},
+		},
+		{
+			name:     "duplicate node",
+			topology: &Topology{Switches: []string{"s1"}, Hosts: []string{"s1"}},
+		},
+		{
+			name:     "unknown link target",
+			topology: &Topology{Switches: []string{"s1"}, Links: []Link{{"s1", "s2"}}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.topology.validate()
+			if test.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
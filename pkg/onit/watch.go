@@ -0,0 +1,225 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package onit
+
+import (
+	"strconv"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// EventType is the type of a change to a watched resource
+type EventType string
+
+const (
+	// EventAdded a resource was added
+	EventAdded EventType = "ADDED"
+
+	// EventUpdated a resource was updated
+	EventUpdated EventType = "UPDATED"
+
+	// EventDeleted a resource was deleted
+	EventDeleted EventType = "DELETED"
+)
+
+// NodeEvent is a change to a node in the cluster
+type NodeEvent struct {
+	Type EventType
+	Node NodeInfo
+}
+
+// PartitionEvent is a change to a partition in the cluster
+type PartitionEvent struct {
+	Type      EventType
+	Partition PartitionInfo
+}
+
+// TestEvent is a change to a test run in the cluster
+type TestEvent struct {
+	Type   EventType
+	Record TestRecord
+}
+
+// WatchNodes watches the onos nodes of the given type, writing changes to the given channel until stop is closed
+func (c *ClusterController) WatchNodes(nodeType NodeType, ch chan<- NodeEvent, stop <-chan struct{}) error {
+	selector := "app=onos"
+	if nodeType != OnosAll {
+		selector = "app=onos,type=" + string(nodeType)
+	}
+
+	// Only report the pods of components enabled in the cluster, ignoring proxies and apps
+	enabled := make(map[string]bool)
+	for _, component := range c.getComponents() {
+		enabled[component.Name()] = true
+	}
+	return c.watchPods(selector, stop, func(eventType EventType, pod *corev1.Pod) {
+		if !enabled[pod.Labels["type"]] {
+			return
+		}
+		select {
+		case ch <- NodeEvent{Type: eventType, Node: newNodeInfo(*pod, NodeType(pod.Labels["type"]))}:
+		case <-stop:
+		}
+	}, func() {
+		close(ch)
+	})
+}
+
+// WatchSimulators watches the simulators in the cluster, writing changes to the given channel until stop is closed
+func (c *ClusterController) WatchSimulators(ch chan<- NodeEvent, stop <-chan struct{}) error {
+	return c.watchPods("type=simulator", stop, func(eventType EventType, pod *corev1.Pod) {
+		select {
+		case ch <- NodeEvent{Type: eventType, Node: newNodeInfo(*pod, Simulator)}:
+		case <-stop:
+		}
+	}, func() {
+		close(ch)
+	})
+}
+
+// WatchPartitions watches the Raft partitions in the cluster, writing changes to the given channel until stop is closed
+func (c *ClusterController) WatchPartitions(ch chan<- PartitionEvent, stop <-chan struct{}) error {
+	return c.watchPods("group=raft", stop, func(eventType EventType, pod *corev1.Pod) {
+		partitionID, err := strconv.Atoi(pod.Labels["partition"])
+		if err != nil {
+			return
+		}
+		partition, err := c.getPartition(partitionID)
+		if err != nil {
+			return
+		}
+
+		// A partition is only removed once all its nodes have been deleted
+		if eventType == EventDeleted && len(partition.Nodes) > 0 {
+			eventType = EventUpdated
+		}
+		select {
+		case ch <- PartitionEvent{Type: eventType, Partition: partition}:
+		case <-stop:
+		}
+	}, func() {
+		close(ch)
+	})
+}
+
+// WatchHistory watches the test runs in the cluster, writing changes to the given channel until stop is closed
+func (c *ClusterController) WatchHistory(ch chan<- TestEvent, stop <-chan struct{}) error {
+	return c.watch(func(options metav1.ListOptions) (watch.Interface, error) {
+		return c.kubeclient.BatchV1().Jobs(c.clusterID).Watch(options)
+	}, stop, func(eventType EventType, object runtime.Object) {
+		job, ok := object.(*batchv1.Job)
+		if !ok {
+			return
+		}
+
+		var record TestRecord
+		var err error
+		if testID, ok := job.Labels[shardLabel]; ok {
+			record, err = c.GetRecord(testID)
+			if eventType == EventDeleted && err == nil {
+				eventType = EventUpdated
+			}
+		} else {
+			record, err = c.getRecord(*job)
+		}
+		if err != nil {
+			return
+		}
+		select {
+		case ch <- TestEvent{Type: eventType, Record: record}:
+		case <-stop:
+		}
+	}, func() {
+		close(ch)
+	})
+}
+
+// watchPods watches the pods matching the given label selector until stop is closed
+func (c *ClusterController) watchPods(selector string, stop <-chan struct{}, handler func(EventType, *corev1.Pod), done func()) error {
+	return c.watch(func(options metav1.ListOptions) (watch.Interface, error) {
+		options.LabelSelector = selector
+		return c.kubeclient.CoreV1().Pods(c.clusterID).Watch(options)
+	}, stop, func(eventType EventType, object runtime.Object) {
+		if pod, ok := object.(*corev1.Pod); ok {
+			handler(eventType, pod)
+		}
+	}, done)
+}
+
+// watch watches the resources returned by the given watch function until stop is closed. Existing resources are
+// reported as added when the watch is started. If the watch is closed by the server, it's resumed from the last
+// observed resource version, or restarted from the current state of the resources if that version has expired.
+// The handler must not block once stop is closed.
+func (c *ClusterController) watch(watchFunc func(metav1.ListOptions) (watch.Interface, error), stop <-chan struct{}, handler func(EventType, runtime.Object), done func()) error {
+	w, err := watchFunc(metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		defer done()
+		var resourceVersion string
+		for {
+			select {
+			case event, ok := <-w.ResultChan():
+				if ok && event.Type == watch.Error {
+					// The last observed resource version is too old to resume from
+					w.Stop()
+					resourceVersion = ""
+					ok = false
+				}
+				if !ok {
+					for {
+						w, err = watchFunc(metav1.ListOptions{ResourceVersion: resourceVersion})
+						if err == nil {
+							break
+						} else if k8serrors.IsGone(err) || k8serrors.IsResourceExpired(err) {
+							resourceVersion = ""
+						}
+						select {
+						case <-stop:
+							return
+						case <-time.After(time.Second):
+						}
+					}
+					continue
+				}
+
+				if object, err := meta.Accessor(event.Object); err == nil {
+					resourceVersion = object.GetResourceVersion()
+				}
+				switch event.Type {
+				case watch.Added:
+					handler(EventAdded, event.Object)
+				case watch.Modified:
+					handler(EventUpdated, event.Object)
+				case watch.Deleted:
+					handler(EventDeleted, event.Object)
+				}
+			case <-stop:
+				w.Stop()
+				return
+			}
+		}
+	}()
+	return nil
+}
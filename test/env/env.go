@@ -17,8 +17,6 @@ package env
 import (
 	"context"
 	"crypto/tls"
-	"crypto/x509"
-	"errors"
 	"fmt"
 	atomixclient "github.com/atomix/atomix-go-client/pkg/client"
 	"github.com/onosproject/onos-config/pkg/northbound/proto"
@@ -26,7 +24,6 @@ import (
 	gnmi "github.com/openconfig/gnmi/client/gnmi"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
-	"io/ioutil"
 	"os"
 	"strings"
 	"time"
@@ -35,12 +32,36 @@ import (
 const (
 	// TestDevicesEnv : environment variable name for devices
 	TestDevicesEnv = "ONOS_CONFIG_TEST_DEVICES"
+
+	// TestDeviceTypesEnv : environment variable name for the id=type:version of each device with a known type
+	TestDeviceTypesEnv = "ONOS_CONFIG_TEST_DEVICE_TYPES"
+)
+
+const (
+	// TestCertsPath : path at which the certificates onit issues for tests are mounted in test pods
+	TestCertsPath = "/etc/onit/certs"
+
+	// TestIdentity : common name of the client certificate issued for the test identity
+	TestIdentity = "onit-security-test"
+
+	// TestIdentityCertKey : secret key for the test identity's client certificate
+	TestIdentityCertKey = "identity.crt"
+
+	// TestIdentityKeyKey : secret key for the test identity's private key
+	TestIdentityKeyKey = "identity.key"
+
+	// TestExpiredCertKey : secret key for the expired client certificate
+	TestExpiredCertKey = "expired.crt"
+
+	// TestExpiredKeyKey : secret key for the expired client certificate's private key
+	TestExpiredKeyKey = "expired.key"
 )
 
 const (
 	clientKeyPath = "/etc/onos-config/certs/client1.key"
 	clientCrtPath = "/etc/onos-config/certs/client1.crt"
 	caCertPath    = "/etc/onos-config/certs/onf.cacrt"
+	clientName    = "client1"
 	configAddress = "onos-config:5150"
 	topoAddress   = "onos-topo:5150"
 )
@@ -53,25 +74,20 @@ func ExecuteCLI(command ...string) ([]string, int) {
 
 // GetCredentials returns gNMI client credentials for the test environment
 func GetCredentials() (*tls.Config, error) {
-	certPool := x509.NewCertPool()
-	ca, err := ioutil.ReadFile(caCertPath)
+	certPool, err := getCertPool()
 	if err != nil {
 		return nil, err
 	}
 
-	if !certPool.AppendCertsFromPEM(ca) {
-		return nil, errors.New("failed to append CA certificates")
-	}
-
 	cert, err := tls.LoadX509KeyPair(clientCrtPath, clientKeyPath)
 	if err != nil {
 		return nil, err
 	}
 
+	// Server certificates are verified against the cluster's CA
 	return &tls.Config{
-		RootCAs:            certPool,
-		Certificates:       []tls.Certificate{cert},
-		InsecureSkipVerify: true,
+		RootCAs:      certPool,
+		Certificates: []tls.Certificate{cert},
 	}, nil
 }
 
@@ -112,7 +128,8 @@ func NewGnmiClient(ctx context.Context, target string) (client.Impl, error) {
 	return gnmi.New(ctx, dest)
 }
 
-// NewGnmiClientForDevice returns a new gNMI client for the test environment
+// NewGnmiClientForDevice returns a new gNMI client for the test environment connected to the given device.
+// Simulated devices serve gNMI in plaintext on their insecure port, so the connection is not secured.
 func NewGnmiClientForDevice(ctx context.Context, address string, target string) (client.Impl, error) {
 	dest, err := GetDestinationForDevice(address, target)
 	if err != nil {
@@ -131,21 +148,35 @@ func GetDevices() []string {
 	return strings.Split(devices, ",")
 }
 
+// GetDevicesByType returns a slice of the names of devices of the given type for the test environment.
+// The type is matched case-insensitively, and if version is empty devices of any version are returned.
+func GetDevicesByType(deviceType string, version string) []string {
+	devices := []string{}
+	for _, entry := range strings.Split(os.Getenv(TestDeviceTypesEnv), ",") {
+		parts := strings.SplitN(entry, "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		typeVersion := strings.SplitN(parts[1], ":", 2)
+		if !strings.EqualFold(typeVersion[0], deviceType) {
+			continue
+		}
+		if version != "" && (len(typeVersion) != 2 || typeVersion[1] != version) {
+			continue
+		}
+		devices = append(devices, parts[0])
+	}
+	return devices
+}
+
 func handleCertArgs() ([]grpc.DialOption, error) {
 	var opts = []grpc.DialOption{}
-	var cert tls.Certificate
-	var err error
 
 	// Load default Certificates
-	cert, err = tls.LoadX509KeyPair(clientCrtPath, clientKeyPath)
+	tlsConfig, err := GetCredentials()
 	if err != nil {
 		return nil, err
 	}
-
-	tlsConfig := &tls.Config{
-		Certificates:       []tls.Certificate{cert},
-		InsecureSkipVerify: true,
-	}
 	opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
 
 	return opts, nil
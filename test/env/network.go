@@ -0,0 +1,76 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package env
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/onosproject/onos-test/pkg/onit/mininet"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SetNetworkLink brings the link between two nodes of a network up or down and returns the resulting state of the link
+func SetNetworkLink(network string, source string, target string, state mininet.LinkState) (mininet.LinkState, error) {
+	return newNetworkCLI(network).SetLink(source, target, state)
+}
+
+// GetNetworkLink returns the state of the link between two nodes of a network
+func GetNetworkLink(network string, source string, target string) (mininet.LinkState, error) {
+	return newNetworkCLI(network).GetLink(source, target)
+}
+
+// SetNetworkSwitch starts or stops a switch of a network and returns the resulting state of the switch
+func SetNetworkSwitch(network string, switchName string, action mininet.SwitchAction) (mininet.SwitchState, error) {
+	port, err := getSwitchPort(network, switchName)
+	if err != nil {
+		return "", err
+	}
+	return newNetworkCLI(network).SetSwitch(switchName, port, action)
+}
+
+// GetNetworkSwitch returns the state of a switch of a network
+func GetNetworkSwitch(network string, switchName string) (mininet.SwitchState, error) {
+	port, err := getSwitchPort(network, switchName)
+	if err != nil {
+		return "", err
+	}
+	return newNetworkCLI(network).GetSwitch(port)
+}
+
+// newNetworkCLI returns a client for the Mininet CLI running in the given network's pod
+func newNetworkCLI(network string) *mininet.CLI {
+	return mininet.NewCLI(func(command []string) ([]byte, error) {
+		output, code := ExecuteCommand(network, command...)
+		if code != 0 {
+			return nil, fmt.Errorf("command exited with status %d", code)
+		}
+		return []byte(strings.Join(output, "\n")), nil
+	})
+}
+
+// getSwitchPort returns the gRPC port of a switch of a network from the switch's service
+func getSwitchPort(network string, switchName string) (int, error) {
+	service, err := mustKubeClientset().CoreV1().Services(GetNamespace()).Get(fmt.Sprintf("%s-%s", network, switchName), metav1.GetOptions{})
+	if err != nil {
+		return 0, err
+	}
+	for _, port := range service.Spec.Ports {
+		if port.Name == "stratum" {
+			return int(port.Port), nil
+		}
+	}
+	return 0, fmt.Errorf("network %s has no switch %s", network, switchName)
+}
@@ -0,0 +1,145 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package env
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"time"
+
+	"github.com/onosproject/onos-test/pkg/onit/pki"
+	"github.com/openconfig/gnmi/client"
+	gnmi "github.com/openconfig/gnmi/client/gnmi"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// GetCredentialsWithoutClientCert returns client credentials that verify the server but present no client certificate
+func GetCredentialsWithoutClientCert() (*tls.Config, error) {
+	certPool, err := getCertPool()
+	if err != nil {
+		return nil, err
+	}
+	return &tls.Config{
+		RootCAs: certPool,
+	}, nil
+}
+
+// GetCredentialsWithUntrustedCert returns client credentials with a certificate signed by a CA unknown to the cluster
+func GetCredentialsWithUntrustedCert() (*tls.Config, error) {
+	ca, err := pki.NewCA("untrusted-ca")
+	if err != nil {
+		return nil, err
+	}
+	return getCredentialsFromCA(ca, pki.Request{
+		CommonName: clientName,
+		Client:     true,
+	})
+}
+
+// GetCredentialsWithExpiredCert returns client credentials with an expired certificate issued by the cluster's CA
+func GetCredentialsWithExpiredCert() (*tls.Config, error) {
+	return getTestCredentials(TestExpiredCertKey, TestExpiredKeyKey)
+}
+
+// GetCredentialsForTestIdentity returns client credentials with a certificate for TestIdentity issued by the
+// cluster's CA
+func GetCredentialsForTestIdentity() (*tls.Config, error) {
+	return getTestCredentials(TestIdentityCertKey, TestIdentityKeyKey)
+}
+
+// NewGnmiClientWithCredentials returns a new gNMI client for the test environment using the given credentials
+func NewGnmiClientWithCredentials(ctx context.Context, target string, credentials *tls.Config) (client.Impl, error) {
+	return gnmi.New(ctx, client.Destination{
+		Addrs:   []string{configAddress},
+		Target:  target,
+		TLS:     credentials,
+		Timeout: 10 * time.Second,
+	})
+}
+
+// GetTopoConnWithCredentials gets a gRPC connection to the topology service using the given credentials
+func GetTopoConnWithCredentials(credentials *tls.Config) (*grpc.ClientConn, error) {
+	return getConnWithCredentials(topoAddress, credentials)
+}
+
+// GetConfigConnWithCredentials gets a gRPC connection to the config service using the given credentials
+func GetConfigConnWithCredentials(credentials *tls.Config) (*grpc.ClientConn, error) {
+	return getConnWithCredentials(configAddress, credentials)
+}
+
+// getConnWithCredentials gets a gRPC connection to the given address using the given credentials
+func getConnWithCredentials(address string, tlsConfig *tls.Config) (*grpc.ClientConn, error) {
+	return grpc.Dial(address, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
+}
+
+// getCredentialsFromCA returns client credentials with a certificate issued by the given CA for the given request
+func getCredentialsFromCA(ca *pki.Certificate, request pki.Request) (*tls.Config, error) {
+	certPool, err := getCertPool()
+	if err != nil {
+		return nil, err
+	}
+
+	issued, err := ca.Issue(request)
+	if err != nil {
+		return nil, err
+	}
+
+	cert, err := tls.X509KeyPair(issued.Cert, issued.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tls.Config{
+		RootCAs:      certPool,
+		Certificates: []tls.Certificate{cert},
+	}, nil
+}
+
+// getTestCredentials returns client credentials with the given certificate and key issued by onit for tests
+func getTestCredentials(certKey string, keyKey string) (*tls.Config, error) {
+	certPool, err := getCertPool()
+	if err != nil {
+		return nil, err
+	}
+
+	cert, err := tls.LoadX509KeyPair(filepath.Join(TestCertsPath, certKey), filepath.Join(TestCertsPath, keyKey))
+	if err != nil {
+		return nil, err
+	}
+
+	return &tls.Config{
+		RootCAs:      certPool,
+		Certificates: []tls.Certificate{cert},
+	}, nil
+}
+
+// getCertPool returns a certificate pool containing the cluster's CA
+func getCertPool() (*x509.CertPool, error) {
+	certPool := x509.NewCertPool()
+	ca, err := ioutil.ReadFile(caCertPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if !certPool.AppendCertsFromPEM(ca) {
+		return nil, errors.New("failed to append CA certificates")
+	}
+	return certPool, nil
+}
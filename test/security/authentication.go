@@ -0,0 +1,112 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package security
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/onosproject/onos-test/pkg/runner"
+	"github.com/onosproject/onos-test/test"
+	"github.com/onosproject/onos-test/test/env"
+	"github.com/onosproject/onos-topo/pkg/northbound/proto"
+	gclient "github.com/openconfig/gnmi/client/gnmi"
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/stretchr/testify/assert"
+)
+
+func init() {
+	test.Registry.RegisterTest("gnmi-authentication", TestGnmiAuthentication, []*runner.TestSuite{SecurityTests})
+	test.Registry.RegisterTest("topo-authentication", TestTopoAuthentication, []*runner.TestSuite{SecurityTests})
+}
+
+// credentialsCase is a set of client credentials and whether the services are expected to accept them
+type credentialsCase struct {
+	description string
+	credentials func() (*tls.Config, error)
+	accepted    bool
+}
+
+// credentialsCases returns the client credentials with which to test authentication
+func credentialsCases() []credentialsCase {
+	return []credentialsCase{
+		{description: "Cluster client certificate", credentials: env.GetCredentials, accepted: true},
+		{description: "Specific client identity", credentials: env.GetCredentialsForTestIdentity, accepted: true},
+		{description: "No client certificate", credentials: env.GetCredentialsWithoutClientCert, accepted: false},
+		{description: "Untrusted client certificate", credentials: env.GetCredentialsWithUntrustedCert, accepted: false},
+		{description: "Expired client certificate", credentials: env.GetCredentialsWithExpiredCert, accepted: false},
+	}
+}
+
+// TestGnmiAuthentication tests that onos-config only accepts gNMI calls from clients with valid certificates
+func TestGnmiAuthentication(t *testing.T) {
+	for _, testCase := range credentialsCases() {
+		t.Run(testCase.description, func(t *testing.T) {
+			credentials, err := testCase.credentials()
+			assert.NoError(t, err)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+			defer cancel()
+
+			// The gNMI client blocks until connected, so the TLS handshake may fail when creating the client
+			client, err := env.NewGnmiClientWithCredentials(ctx, "", credentials)
+			if err == nil {
+				defer client.Close()
+				_, err = client.(*gclient.Client).Capabilities(ctx, &gpb.CapabilityRequest{})
+			}
+
+			if testCase.accepted {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+// TestTopoAuthentication tests that onos-topo only accepts gRPC calls from clients with valid certificates
+func TestTopoAuthentication(t *testing.T) {
+	for _, testCase := range credentialsCases() {
+		t.Run(testCase.description, func(t *testing.T) {
+			credentials, err := testCase.credentials()
+			assert.NoError(t, err)
+
+			conn, err := env.GetTopoConnWithCredentials(credentials)
+			assert.NoError(t, err)
+			defer conn.Close()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+			defer cancel()
+
+			client := proto.NewDeviceServiceClient(conn)
+			list, err := client.List(ctx, &proto.ListRequest{})
+			if err == nil {
+				_, err = list.Recv()
+				if err == io.EOF {
+					err = nil
+				}
+			}
+
+			if testCase.accepted {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}